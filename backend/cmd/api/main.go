@@ -2,10 +2,14 @@ package main
 
 import (
 	"log"
+	"time"
 
 	"hh_puzzle/internal/config"
+	"hh_puzzle/internal/coop"
 	"hh_puzzle/internal/database"
 	"hh_puzzle/internal/handlers"
+	"hh_puzzle/internal/jobs"
+	"hh_puzzle/internal/payments"
 	"hh_puzzle/internal/repository"
 	"hh_puzzle/internal/routes"
 	"hh_puzzle/internal/services"
@@ -28,23 +32,92 @@ func main() {
 	log.Println("✅ Database connected")
 
 	// Initialize repositories
+	txManager := repository.NewTxManager(database.DB)
 	userRepo := repository.NewUserRepository(database.DB)
 	puzzleRepo := repository.NewPuzzleRepository(database.DB)
 	attemptRepo := repository.NewAttemptRepository(database.DB)
+	hintRepo := repository.NewHintRepository(database.DB)
+	pauseRepo := repository.NewPauseRepository(database.DB)
+	bestRepo := repository.NewPuzzleBestRepository(database.DB)
+	memberRepo := repository.NewAttemptMemberRepository(database.DB)
+	leaderboardRepo := repository.NewLeaderboardRepository(database.DB)
+	factRepo := repository.NewFactRepository(database.DB)
+	packRepo := repository.NewPuzzlePackRepository(database.DB)
+	purchaseRepo := repository.NewPurchaseRepository(database.DB)
+	musicRepo := repository.NewMusicRepository(database.DB)
+	streakRepo := repository.NewStreakRepository(database.DB)
+	achievementRepo := repository.NewAchievementRepository(database.DB)
+	idempotencyRepo := repository.NewIdempotencyRepository(database.DB)
 	log.Println("✅ Repositories initialized")
 
 	// Initialize services
 	authService := services.NewAuthService(userRepo)
 	userService := services.NewUserService(userRepo)
-	puzzleService := services.NewPuzzleService(puzzleRepo)
-	attemptService := services.NewAttemptService(attemptRepo, userRepo, puzzleRepo)
+	subscriptionService := services.NewSubscriptionService(purchaseRepo)
+	entitlementService := services.NewEntitlementService(purchaseRepo, packRepo, userRepo, subscriptionService)
+	puzzleService := services.NewPuzzleService(
+		puzzleRepo,
+		packRepo,
+		attemptRepo,
+		userRepo,
+		entitlementService,
+	)
+	leaderboardService := services.NewLeaderboardService(leaderboardRepo)
+	streakService := services.NewStreakService(streakRepo, userRepo, txManager)
+	factService := services.NewFactService(factRepo)
+	achievementService := services.NewAchievementService(achievementRepo)
+	scoringService := services.NewScoringService(services.DefaultScoringPolicy())
+	attemptService := services.NewAttemptService(
+		txManager,
+		attemptRepo,
+		userRepo,
+		puzzleRepo,
+		hintRepo,
+		pauseRepo,
+		bestRepo,
+		memberRepo,
+		entitlementService,
+		streakService,
+		leaderboardService,
+		factService,
+		achievementService,
+		scoringService,
+	)
+	// Without a real provider, production refuses purchases and payment webhooks
+	var paymentProviders []payments.Provider
+	if cfg.UsesLocalPayments() {
+		paymentProviders = append(paymentProviders, payments.NewLocalProvider(cfg.Payments.WebhookSecret))
+	}
+	purchaseService := services.NewPurchaseService(
+		purchaseRepo,
+		packRepo,
+		entitlementService,
+		subscriptionService,
+		paymentProviders...,
+	)
+	coopService := services.NewCoopService(txManager, attemptRepo, memberRepo, puzzleRepo, entitlementService)
+	musicService := services.NewMusicService(musicRepo, puzzleRepo, userRepo)
+	dailyScheduleService := services.NewDailyScheduleService(puzzleRepo)
 	log.Println("✅ Services initialized")
 
+	// Start the hub that relays co-op play between players of shared attempts
+	hub := coop.NewHub(coop.RoomIdleTimeout)
+	go hub.Run()
+	defer hub.Stop()
+
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService, userService)
-	userHandler := handlers.NewUserHandler(userService)
+	userHandler := handlers.NewUserHandler(userService, streakService)
 	puzzleHandler := handlers.NewPuzzleHandler(puzzleService)
-	attemptHandler := handlers.NewAttemptHandler(attemptService)
+	attemptHandler := handlers.NewAttemptHandler(attemptService, hub)
+	coopHandler := handlers.NewCoopHandler(coopService, attemptService, hub)
+	leaderboardHandler := handlers.NewLeaderboardHandler(leaderboardService)
+	factHandler := handlers.NewFactHandler(factService)
+	purchaseHandler := handlers.NewPurchaseHandler(purchaseService)
+	subscriptionHandler := handlers.NewSubscriptionHandler(subscriptionService)
+	musicHandler := handlers.NewMusicHandler(musicService)
+	achievementHandler := handlers.NewAchievementHandler(achievementService)
+	adminHandler := handlers.NewAdminHandler(puzzleService, purchaseService, attemptService)
 	log.Println("✅ Handlers initialized")
 
 	// Setup routes
@@ -53,9 +126,56 @@ func main() {
 		userHandler,
 		puzzleHandler,
 		attemptHandler,
+		coopHandler,
+		leaderboardHandler,
+		factHandler,
+		purchaseHandler,
+		subscriptionHandler,
+		musicHandler,
+		achievementHandler,
+		adminHandler,
+		idempotencyRepo,
+		cfg.Admin.Emails,
 	)
 	log.Println("✅ Routes configured")
 
+	// Start background jobs
+	scheduler := jobs.NewScheduler()
+	scheduler.Add("rank-weekly-leaderboards", time.Hour, func() error {
+		return leaderboardService.CloseFinishedWeeks(time.Now())
+	})
+	scheduler.Add("expire-subscriptions", 15*time.Minute, func() error {
+		expired, err := subscriptionService.ExpireLapsed(time.Now())
+		if expired > 0 {
+			log.Printf("Expired %d subscriptions", expired)
+		}
+		return err
+	})
+	scheduler.Add("schedule-daily-challenges", 6*time.Hour, func() error {
+		schedule, err := dailyScheduleService.ScheduleDays(time.Now(), 7, false)
+		if err != nil {
+			return err
+		}
+		for _, gap := range schedule.Gaps {
+			log.Printf("⚠️  No daily challenge for %s: %s", gap.Date.Format("2006-01-02"), gap.Reason)
+		}
+		return nil
+	})
+	scheduler.Add("recalculate-par-times", 24*time.Hour, func() error {
+		updated, err := puzzleService.RecalculateParTimes()
+		if updated > 0 {
+			log.Printf("Recalculated par times of %d puzzles", updated)
+		}
+		return err
+	})
+	scheduler.Add("purge-idempotency-keys", time.Hour, func() error {
+		_, err := idempotencyRepo.DeleteExpired(time.Now())
+		return err
+	})
+	scheduler.Start()
+	defer scheduler.Stop()
+	log.Println("✅ Background jobs started")
+
 	// Start server
 	port := cfg.Server.Port
 	if port == "" {
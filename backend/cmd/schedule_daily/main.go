@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"hh_puzzle/internal/config"
+	"hh_puzzle/internal/database"
+	"hh_puzzle/internal/repository"
+	"hh_puzzle/internal/services"
+)
+
+func main() {
+	days := flag.Int("days", 14, "number of days to schedule, starting with the start date")
+	startDate := flag.String("start", "", "first date to schedule (YYYY-MM-DD), defaults to today")
+	dryRun := flag.Bool("dry-run", false, "print the schedule without saving it")
+	flag.Parse()
+
+	if *days < 1 {
+		log.Fatal("days must be at least 1")
+	}
+
+	start := time.Now().UTC()
+	if *startDate != "" {
+		parsed, err := time.Parse("2006-01-02", *startDate)
+		if err != nil {
+			log.Fatalf("Invalid start date: %v", err)
+		}
+		start = parsed
+	}
+
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	// Connect to database
+	if err := database.Connect(cfg); err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.Close()
+
+	fmt.Println("📅 HH_Puzzle - Daily Challenge Scheduler")
+	fmt.Println("=========================================")
+	if *dryRun {
+		fmt.Println("Dry run: nothing will be saved")
+	}
+	fmt.Println()
+
+	scheduler := services.NewDailyScheduleService(repository.NewPuzzleRepository(database.DB))
+	schedule, err := scheduler.ScheduleDays(start, *days, *dryRun)
+	if err != nil {
+		log.Fatalf("Failed to schedule daily challenges: %v", err)
+	}
+
+	newCount := 0
+	for _, day := range schedule.Days {
+		marker := "+"
+		if day.Existing {
+			marker = "="
+		} else {
+			newCount++
+		}
+		fmt.Printf("%s %s  #%-5d %-12s %-6s %-10s %s\n",
+			marker, day.Date.Format("2006-01-02"), day.PuzzleID, day.Difficulty, day.Decade, day.Region, day.Title)
+	}
+
+	for _, gap := range schedule.Gaps {
+		fmt.Printf("! %s  %s\n", gap.Date.Format("2006-01-02"), gap.Reason)
+	}
+
+	fmt.Println()
+	if *dryRun {
+		fmt.Printf("Would schedule %d new day(s)", newCount)
+	} else {
+		fmt.Printf("Scheduled %d new day(s)", newCount)
+	}
+	fmt.Printf(", %d already scheduled, %d gap(s)\n", len(schedule.Days)-newCount, len(schedule.Gaps))
+
+	if len(schedule.Gaps) > 0 {
+		os.Exit(1)
+	}
+}
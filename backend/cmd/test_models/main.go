@@ -66,12 +66,18 @@ retrievedUser.Profile.MusicVolume)
 // Test 3: Create a puzzle
 fmt.Println("\nTest 3: Creating a puzzle...")
 now := time.Now()
+cluesAcross := models.ClueList{
+{Number: 1, Direction: models.DirectionAcross, Text: "Notorious B.I.G.'s debut album, Ready to ___", Length: 3, X: 0, Y: 0, Answer: "DIE"},
+}
+cluesDown := models.ClueList{
+{Number: 1, Direction: models.DirectionDown, Text: "Dr. ___, producer of The Chronic", Length: 3, X: 0, Y: 0, Answer: "DRE"},
+}
 puzzle := &models.Puzzle{
 Title:              "Test Puzzle - 90s Hip-Hop",
 Description:        "A test puzzle about 90s hip-hop",
-GridData:           models.JSONB{"width": 5, "height": 5, "cells": []interface{}{}},
-CluesAcross:        models.JSONB{"1": "Notorious B.I.G.'s debut album"},
-CluesDown:          models.JSONB{"1": "Dr. Dre's 1992 album"},
+GridData:           models.BuildPuzzleGrid(cluesAcross, cluesDown),
+CluesAcross:        cluesAcross,
+CluesDown:          cluesDown,
 Difficulty:         "beginner",
 Decade:             "90s",
 Region:             "NYC",
@@ -114,7 +120,7 @@ fmt.Printf("✓ Puzzle attempt created with ID: %d\n", attempt.ID)
 // Test 6: Update attempt (simulate completion)
 fmt.Println("\nTest 6: Updating attempt (completing puzzle)...")
 completedAt := time.Now()
-completionTime := 300 // 5 minutes
+completionTime := models.Minutes(5)
 accuracy := 95.5
 attempt.IsCompleted = true
 attempt.CompletedAt = &completedAt
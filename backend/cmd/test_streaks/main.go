@@ -0,0 +1,170 @@
+// backend/cmd/test_streaks/main.go
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"hh_puzzle/internal/models"
+	"hh_puzzle/internal/services"
+)
+
+// play is one puzzle completion at an instant, in the zone the player had set
+type play struct {
+	at       string // RFC 3339
+	timezone string
+}
+
+type scenario struct {
+	name         string
+	freezes      int // streak freezes held before the first play
+	plays        []play
+	streaks      []int // expected streak after each play
+	finalFreezes int
+}
+
+func main() {
+	fmt.Println("=== Testing timezone-aware streaks and freezes ===")
+	fmt.Println()
+
+	scenarios := []scenario{
+		{
+			name: "LA evening counts as the local day, not the next UTC day",
+			plays: []play{
+				{"2026-03-02T16:30:00-08:00", "America/Los_Angeles"}, // 00:30 UTC Mar 3
+				{"2026-03-03T09:00:00-08:00", "America/Los_Angeles"},
+				{"2026-03-03T20:00:00-08:00", "America/Los_Angeles"},
+			},
+			streaks: []int{1, 2, 2},
+		},
+		{
+			name: "spring forward in LA keeps consecutive days",
+			plays: []play{
+				{"2026-03-07T23:30:00-08:00", "America/Los_Angeles"},
+				{"2026-03-08T03:30:00-07:00", "America/Los_Angeles"}, // 23-hour day
+				{"2026-03-09T23:59:00-07:00", "America/Los_Angeles"},
+			},
+			streaks: []int{1, 2, 3},
+		},
+		{
+			name: "fall back in London keeps consecutive days",
+			plays: []play{
+				{"2026-10-24T23:45:00+01:00", "Europe/London"},
+				{"2026-10-25T23:45:00+00:00", "Europe/London"}, // 25-hour day
+				{"2026-10-26T00:15:00+00:00", "Europe/London"},
+			},
+			streaks: []int{1, 2, 3},
+		},
+		{
+			name: "skipping a local day breaks the streak",
+			plays: []play{
+				{"2026-06-01T12:00:00-04:00", "America/New_York"},
+				{"2026-06-03T12:00:00-04:00", "America/New_York"},
+			},
+			streaks: []int{1, 1},
+		},
+		{
+			name: "flying New York to LA continues on the next local day",
+			plays: []play{
+				{"2026-05-10T23:00:00-04:00", "America/New_York"},
+				{"2026-05-11T20:00:00-07:00", "America/Los_Angeles"},
+			},
+			streaks: []int{1, 2},
+		},
+		{
+			name: "flying Tokyo to Honolulu repeats a date without breaking",
+			plays: []play{
+				{"2026-07-01T08:00:00+09:00", "Asia/Tokyo"},
+				{"2026-07-02T09:00:00+09:00", "Asia/Tokyo"},
+				{"2026-07-01T20:00:00-10:00", "Pacific/Honolulu"}, // back to Jul 1 locally
+				{"2026-07-02T20:00:00-10:00", "Pacific/Honolulu"},
+				{"2026-07-03T20:00:00-10:00", "Pacific/Honolulu"},
+			},
+			streaks: []int{1, 2, 2, 2, 3},
+		},
+		{
+			name: "flying Honolulu to Auckland skips a date and resets",
+			plays: []play{
+				{"2026-08-01T10:00:00-10:00", "Pacific/Honolulu"},
+				{"2026-08-03T09:00:00+12:00", "Pacific/Auckland"}, // only 13 hours later
+			},
+			streaks: []int{1, 1},
+		},
+		{
+			name:    "a freeze covers a missed day",
+			freezes: 1,
+			plays: []play{
+				{"2026-09-01T12:00:00-07:00", "America/Los_Angeles"},
+				{"2026-09-03T12:00:00-07:00", "America/Los_Angeles"},
+			},
+			streaks:      []int{1, 2},
+			finalFreezes: 0,
+		},
+		{
+			name:    "too few freezes for the gap resets and keeps them",
+			freezes: 1,
+			plays: []play{
+				{"2026-09-01T12:00:00Z", "UTC"},
+				{"2026-09-04T12:00:00Z", "UTC"},
+			},
+			streaks:      []int{1, 1},
+			finalFreezes: 1,
+		},
+		{
+			name: "a seven day streak earns a freeze",
+			plays: []play{
+				{"2026-09-01T08:00:00Z", "UTC"},
+				{"2026-09-02T08:00:00Z", "UTC"},
+				{"2026-09-03T08:00:00Z", "UTC"},
+				{"2026-09-04T08:00:00Z", "UTC"},
+				{"2026-09-05T08:00:00Z", "UTC"},
+				{"2026-09-06T08:00:00Z", "UTC"},
+				{"2026-09-07T08:00:00Z", "UTC"},
+			},
+			streaks:      []int{1, 2, 3, 4, 5, 6, 7},
+			finalFreezes: 1,
+		},
+	}
+
+	failed := 0
+	for _, sc := range scenarios {
+		if err := run(sc); err != nil {
+			failed++
+			fmt.Printf("✗ %s: %v\n", sc.name, err)
+			continue
+		}
+		fmt.Printf("✓ %s\n", sc.name)
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		fmt.Printf("%d of %d scenarios failed\n", failed, len(scenarios))
+		os.Exit(1)
+	}
+	fmt.Printf("All %d scenarios passed\n", len(scenarios))
+}
+
+func run(sc scenario) error {
+	profile := &models.UserProfile{StreakFreezes: sc.freezes}
+
+	for i, p := range sc.plays {
+		at, err := time.Parse(time.RFC3339, p.at)
+		if err != nil {
+			return err
+		}
+		profile.Timezone = p.timezone
+
+		today := services.LocalDate(at, services.UserLocation(profile))
+		update := services.AdvanceStreak(profile, today)
+		if update.Streak != sc.streaks[i] {
+			return fmt.Errorf("play %d on %s: got streak %d, want %d",
+				i+1, today.Format("2006-01-02"), update.Streak, sc.streaks[i])
+		}
+	}
+
+	if profile.StreakFreezes != sc.finalFreezes {
+		return fmt.Errorf("got %d freezes left, want %d", profile.StreakFreezes, sc.finalFreezes)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// backend/cmd/test_timer/main.go
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"hh_puzzle/internal/models"
+	"hh_puzzle/internal/services"
+)
+
+// pause is a span in minutes after the attempt started; a negative end
+// leaves the pause open
+type pause struct {
+	from, to int
+}
+
+type scenario struct {
+	name     string
+	pauses   []pause
+	submitAt int            // minutes after the attempt started
+	want     models.Seconds // expected completion time
+	wantErr  error
+}
+
+func main() {
+	fmt.Println("=== Testing server-side attempt timing ===")
+	fmt.Println()
+
+	scenarios := []scenario{
+		{
+			name:     "an attempt without pauses counts its whole time",
+			submitAt: 10,
+			want:     600,
+		},
+		{
+			name:     "a resumed pause is left out",
+			pauses:   []pause{{2, 7}},
+			submitAt: 10,
+			want:     300,
+		},
+		{
+			name:     "submitting while paused is rejected",
+			pauses:   []pause{{1, -1}},
+			submitAt: 30,
+			wantErr:  services.ErrAttemptPaused,
+		},
+		{
+			name:     "pause, resume and submit counts only the time played",
+			pauses:   []pause{{1, 29}},
+			submitAt: 30,
+			want:     120,
+		},
+	}
+
+	failed := 0
+	for _, sc := range scenarios {
+		if err := run(sc); err != nil {
+			failed++
+			fmt.Printf("✗ %s: %v\n", sc.name, err)
+			continue
+		}
+		fmt.Printf("✓ %s\n", sc.name)
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		fmt.Printf("%d of %d scenarios failed\n", failed, len(scenarios))
+		os.Exit(1)
+	}
+	fmt.Printf("All %d scenarios passed\n", len(scenarios))
+}
+
+func run(sc scenario) error {
+	startedAt := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	minutes := func(m int) time.Time {
+		return startedAt.Add(time.Duration(m) * time.Minute)
+	}
+
+	var pauses []models.AttemptPause
+	for _, p := range sc.pauses {
+		pause := models.AttemptPause{PausedAt: minutes(p.from)}
+		if p.to >= 0 {
+			resumedAt := minutes(p.to)
+			pause.ResumedAt = &resumedAt
+		}
+		pauses = append(pauses, pause)
+	}
+
+	got, err := services.SubmittedTime(startedAt, pauses, minutes(sc.submitAt))
+	if !errors.Is(err, sc.wantErr) {
+		return fmt.Errorf("got error %v, want %v", err, sc.wantErr)
+	}
+	if got != sc.want {
+		return fmt.Errorf("got %d seconds, want %d", got, sc.want)
+	}
+	return nil
+}
@@ -3,16 +3,27 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	Database DatabaseConfig
-	Server   ServerConfig
+	Environment string // development, test or production
+	Database    DatabaseConfig
+	Server      ServerConfig
+	Admin       AdminConfig
+	Payments    PaymentsConfig
 }
 
+// Environments the application can run in
+const (
+	EnvDevelopment = "development"
+	EnvTest        = "test"
+	EnvProduction  = "production"
+)
+
 // DatabaseConfig holds database connection settings
 type DatabaseConfig struct {
 	Host     string
@@ -29,12 +40,23 @@ type ServerConfig struct {
 	Host string
 }
 
+// AdminConfig holds settings for admin and authoring access
+type AdminConfig struct {
+	Emails []string // accounts allowed to use admin endpoints
+}
+
+// PaymentsConfig holds payment provider settings
+type PaymentsConfig struct {
+	WebhookSecret string // shared secret for signing local provider webhooks
+}
+
 // Load reads configuration from environment variables or uses defaults
 func Load() (*Config, error) {
 	// Load .env file if it exists (ignore error if file doesn't exist)
 	_ = godotenv.Load()
 
 	config := &Config{
+		Environment: getEnv("APP_ENV", EnvProduction),
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
 			Port:     getEnv("DB_PORT", "5433"),
@@ -47,16 +69,36 @@ func Load() (*Config, error) {
 			Port: getEnv("SERVER_PORT", "8080"),
 			Host: getEnv("SERVER_HOST", "localhost"),
 		},
+		Admin: AdminConfig{
+			Emails: getEnvList("ADMIN_EMAILS"),
+		},
+		Payments: PaymentsConfig{
+			WebhookSecret: os.Getenv("PAYMENT_WEBHOOK_SECRET"),
+		},
 	}
 
 	// Validate required fields
+	switch config.Environment {
+	case EnvDevelopment, EnvTest, EnvProduction:
+	default:
+		return nil, fmt.Errorf("unknown environment %q", config.Environment)
+	}
 	if config.Database.Password == "" {
 		return nil, fmt.Errorf("database password is required")
 	}
+	if config.Payments.WebhookSecret == "" {
+		return nil, fmt.Errorf("payment webhook secret is required")
+	}
 
 	return config, nil
 }
 
+// UsesLocalPayments reports whether the local payment provider, which moves no
+// money, may take payments. It is never enabled in production.
+func (c *Config) UsesLocalPayments() bool {
+	return c.Environment == EnvDevelopment || c.Environment == EnvTest
+}
+
 // getEnv gets an environment variable or returns a default value
 func getEnv(key, defaultValue string) string {
 	value := os.Getenv(key)
@@ -65,3 +107,14 @@ func getEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+// getEnvList reads a comma-separated environment variable into a list
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, strings.ToLower(value))
+		}
+	}
+	return values
+}
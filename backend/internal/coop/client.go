@@ -0,0 +1,66 @@
+package coop
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// ErrInvalidMessage is returned by Receive for a message that is not valid
+// JSON of the expected shape. The connection stays usable.
+var ErrInvalidMessage = errors.New("invalid message")
+
+const (
+	// ReadTimeout is how long a connection may stay silent. Clients send a
+	// ping message while the player is idle to keep it open.
+	ReadTimeout = 90 * time.Second
+
+	writeTimeout = 10 * time.Second
+)
+
+// Client is one player's connection to a room
+type Client struct {
+	AttemptID uint
+	UserID    uint
+
+	conn    *websocket.Conn
+	send    chan Message // closed by the hub when the client leaves the room
+	welcome Message
+}
+
+// Receive reads the next message from the player into v
+func (c *Client) Receive(v interface{}) error {
+	if err := c.conn.SetReadDeadline(time.Now().Add(ReadTimeout)); err != nil {
+		return err
+	}
+
+	var data []byte
+	if err := websocket.Message.Receive(c.conn, &data); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return ErrInvalidMessage
+	}
+	return nil
+}
+
+// writeLoop writes queued messages to the connection until the hub closes the
+// queue, then closes the connection so the reading side stops too
+func (c *Client) writeLoop() {
+	defer c.conn.Close()
+
+	for message := range c.send {
+		if err := c.conn.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
+			break
+		}
+		if err := websocket.JSON.Send(c.conn, message); err != nil {
+			break
+		}
+	}
+
+	// Let the hub finish closing the queue after a failed write
+	for range c.send {
+	}
+}
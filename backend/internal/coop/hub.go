@@ -0,0 +1,254 @@
+package coop
+
+import (
+	"sort"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// RoomIdleTimeout is how long a room outlives its last connection, so players
+// who drop and reconnect find their cursors where they left them
+const RoomIdleTimeout = 10 * time.Minute
+
+// sendBuffer is how many messages can wait for a slow connection before the
+// hub drops it
+const sendBuffer = 64
+
+// publication is a message for the players of a room. When to is set only
+// that client gets it; except is left out.
+type publication struct {
+	attemptID uint
+	message   Message
+	to        *Client
+	except    *Client
+}
+
+// room is the in-memory state of a shared attempt
+type room struct {
+	clients   map[uint]*Client // by user; a reconnect replaces the user's previous connection
+	cursors   map[uint]Cursor
+	idleSince time.Time // when the last player left; zero while anyone is connected
+}
+
+// Hub keeps a room for every shared attempt with connected players and relays
+// messages between them. Rooms are only touched by the goroutine running
+// Run; everything else talks to it over channels.
+type Hub struct {
+	connect     chan *Client
+	disconnect  chan *Client
+	publish     chan publication
+	stop        chan struct{}
+	done        chan struct{}
+	rooms       map[uint]*room
+	idleTimeout time.Duration
+}
+
+// NewHub creates a hub whose empty rooms are dropped after idleTimeout
+func NewHub(idleTimeout time.Duration) *Hub {
+	return &Hub{
+		connect:     make(chan *Client),
+		disconnect:  make(chan *Client),
+		publish:     make(chan publication, sendBuffer),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+		rooms:       make(map[uint]*room),
+		idleTimeout: idleTimeout,
+	}
+}
+
+// Run manages the rooms until Stop is called
+func (h *Hub) Run() {
+	defer close(h.done)
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case client := <-h.connect:
+			h.addClient(client)
+		case client := <-h.disconnect:
+			h.removeClient(client)
+		case p := <-h.publish:
+			h.deliver(p)
+		case now := <-ticker.C:
+			h.dropIdleRooms(now)
+		case <-h.stop:
+			for _, r := range h.rooms {
+				for _, client := range r.clients {
+					close(client.send)
+				}
+			}
+			h.rooms = nil
+			return
+		}
+	}
+}
+
+// Stop closes every connection and ends Run
+func (h *Hub) Stop() {
+	close(h.stop)
+	<-h.done
+}
+
+// Connect adds a player's connection to the attempt's room. The welcome
+// message is sent first, completed with the players online and their cursors.
+func (h *Hub) Connect(attemptID, userID uint, conn *websocket.Conn, welcome Message) *Client {
+	client := &Client{
+		AttemptID: attemptID,
+		UserID:    userID,
+		conn:      conn,
+		send:      make(chan Message, sendBuffer),
+		welcome:   welcome,
+	}
+	go client.writeLoop()
+
+	select {
+	case h.connect <- client:
+	case <-h.done:
+		close(client.send)
+	}
+	return client
+}
+
+// Disconnect removes a connection from its room
+func (h *Hub) Disconnect(client *Client) {
+	select {
+	case h.disconnect <- client:
+	case <-h.done:
+	}
+}
+
+// Publish sends a message to every player connected to the attempt. It does
+// nothing when no one is.
+func (h *Hub) Publish(attemptID uint, message Message) {
+	h.queue(publication{attemptID: attemptID, message: message})
+}
+
+// Broadcast sends a message from a client to the other players in its room
+func (h *Hub) Broadcast(from *Client, message Message) {
+	h.queue(publication{attemptID: from.AttemptID, message: message, except: from})
+}
+
+// Reply sends a message to a single client
+func (h *Hub) Reply(to *Client, message Message) {
+	h.queue(publication{attemptID: to.AttemptID, message: message, to: to})
+}
+
+func (h *Hub) queue(p publication) {
+	select {
+	case h.publish <- p:
+	case <-h.done:
+	}
+}
+
+func (h *Hub) addClient(client *Client) {
+	r, ok := h.rooms[client.AttemptID]
+	if !ok {
+		r = &room{
+			clients: make(map[uint]*Client),
+			cursors: make(map[uint]Cursor),
+		}
+		h.rooms[client.AttemptID] = r
+	}
+	r.idleSince = time.Time{}
+
+	// A reconnect replaces the player's old connection without announcing them again
+	previous, reconnected := r.clients[client.UserID]
+	if reconnected {
+		close(previous.send)
+	}
+	r.clients[client.UserID] = client
+
+	welcome := client.welcome
+	welcome.Type = MessageWelcome
+	welcome.Online = r.online()
+	welcome.Cursors = make(map[uint]Cursor, len(r.cursors))
+	for userID, cursor := range r.cursors {
+		welcome.Cursors[userID] = cursor
+	}
+	h.send(r, client, welcome)
+
+	if !reconnected {
+		h.deliver(publication{
+			attemptID: client.AttemptID,
+			message:   Message{Type: MessageJoined, UserID: client.UserID},
+			except:    client,
+		})
+	}
+}
+
+func (h *Hub) removeClient(client *Client) {
+	r, ok := h.rooms[client.AttemptID]
+	if !ok || r.clients[client.UserID] != client {
+		// Already replaced by a reconnect or dropped as too slow
+		return
+	}
+	h.drop(r, client)
+}
+
+// drop closes a client's connection and tells the rest of the room it left
+func (h *Hub) drop(r *room, client *Client) {
+	delete(r.clients, client.UserID)
+	close(client.send)
+	if len(r.clients) == 0 {
+		r.idleSince = time.Now()
+		return
+	}
+
+	h.deliver(publication{
+		attemptID: client.AttemptID,
+		message:   Message{Type: MessageLeft, UserID: client.UserID},
+	})
+}
+
+func (h *Hub) deliver(p publication) {
+	r, ok := h.rooms[p.attemptID]
+	if !ok {
+		return
+	}
+
+	if p.message.Type == MessageCursor && p.message.Cursor != nil {
+		r.cursors[p.message.UserID] = *p.message.Cursor
+	}
+
+	if p.to != nil {
+		if r.clients[p.to.UserID] == p.to {
+			h.send(r, p.to, p.message)
+		}
+		return
+	}
+	for _, client := range r.clients {
+		if client != p.except {
+			h.send(r, client, p.message)
+		}
+	}
+}
+
+// send queues a message for a client, dropping the client when its buffer is full
+func (h *Hub) send(r *room, client *Client, message Message) {
+	select {
+	case client.send <- message:
+	default:
+		h.drop(r, client)
+	}
+}
+
+func (h *Hub) dropIdleRooms(now time.Time) {
+	for attemptID, r := range h.rooms {
+		if len(r.clients) == 0 && now.Sub(r.idleSince) >= h.idleTimeout {
+			delete(h.rooms, attemptID)
+		}
+	}
+}
+
+// online returns the users connected to the room in ID order
+func (r *room) online() []uint {
+	online := make([]uint, 0, len(r.clients))
+	for userID := range r.clients {
+		online = append(online, userID)
+	}
+	sort.Slice(online, func(i, j int) bool { return online[i] < online[j] })
+	return online
+}
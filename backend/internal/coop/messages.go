@@ -0,0 +1,74 @@
+package coop
+
+import "hh_puzzle/internal/services"
+
+// Message types sent to the players in a room
+const (
+	MessageWelcome   = "welcome"   // first message after connecting, with the room's state
+	MessageJoined    = "joined"    // a player connected
+	MessageLeft      = "left"      // a player's last connection closed
+	MessageCells     = "cells"     // cells were filled or cleared
+	MessageCursor    = "cursor"    // a player moved their cursor
+	MessageHint      = "hint"      // a player used a hint
+	MessageCompleted = "completed" // the attempt was submitted
+	MessageError     = "error"     // only sent to the player whose message failed
+)
+
+// Cursor is the square and direction a player is working on
+type Cursor struct {
+	X         int    `json:"x"`
+	Y         int    `json:"y"`
+	Direction string `json:"direction"` // across, down
+}
+
+// Message is sent to players over the WebSocket.
+//
+// Progress carries grid changes as a delta from Since to Version. A player
+// whose grid is at a version from Since up to before Version applies the
+// cells and moves to Version; one already at Version or later ignores them.
+// A player behind Since missed a change and fetches the progress since its
+// own version. Since 0 means the cells are the whole grid.
+type Message struct {
+	Type   string `json:"type"`
+	UserID uint   `json:"user_id,omitempty"` // player who caused the message
+
+	Progress *services.ProgressDelta `json:"progress,omitempty"`
+	Cursor   *Cursor                 `json:"cursor,omitempty"`
+	Hint     *services.HintResult    `json:"hint,omitempty"`
+	Result   *services.AttemptResult `json:"result,omitempty"`
+	Online   []uint                  `json:"online,omitempty"`  // connected players, for welcome
+	Cursors  map[uint]Cursor         `json:"cursors,omitempty"` // last cursor of each player, for welcome
+	Shared   *services.SharedAttempt `json:"shared,omitempty"`  // members and split rule, for welcome
+
+	Code  string `json:"code,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// CellsMessage announces cells a player changed
+func CellsMessage(userID uint, delta *services.ProgressDelta) Message {
+	return Message{Type: MessageCells, UserID: userID, Progress: delta}
+}
+
+// HintMessage announces a hint; the cells it revealed form one version
+func HintMessage(userID, attemptID uint, result *services.HintResult) Message {
+	message := Message{Type: MessageHint, UserID: userID, Hint: result}
+	if len(result.Revealed) > 0 {
+		message.Progress = &services.ProgressDelta{
+			AttemptID: attemptID,
+			Version:   result.Version,
+			Since:     result.Version - 1,
+			Cells:     result.Revealed,
+		}
+	}
+	return message
+}
+
+// CompletedMessage announces the result of submitting the attempt
+func CompletedMessage(userID uint, result *services.AttemptResult) Message {
+	return Message{Type: MessageCompleted, UserID: userID, Result: result}
+}
+
+// ErrorMessage tells a player why their message failed
+func ErrorMessage(code, message string) Message {
+	return Message{Type: MessageError, Code: code, Error: message}
+}
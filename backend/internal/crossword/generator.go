@@ -1,9 +1,8 @@
 package crossword
 
 import (
-	"encoding/json"
 	"fmt"
-	"strconv"
+	"sort"
 
 	"github.com/warmans/go-crossword"
 	"hh_puzzle/internal/models"
@@ -61,46 +60,48 @@ func (g *HipHopGenerator) convertToPuzzle(
 	difficulty string,
 ) *models.Puzzle {
 
-	// Convert grid to JSONB format
-	gridData := make(models.JSONB)
-	gridJSON, _ := json.Marshal(cw.Grid)
-	var gridMap map[string]interface{}
-	json.Unmarshal(gridJSON, &gridMap)
-	gridData = gridMap
-
-	// Separate clues into across and down
-	cluesAcross := make(models.JSONB)
-	cluesDown := make(models.JSONB)
+	// Number word starts in reading order and build the typed clues
+	numbers := numberPlacements(cw.Words)
+	cluesAcross := models.ClueList{}
+	cluesDown := models.ClueList{}
 
 	for _, placement := range cw.Words {
-		clueID := placement.ClueID()
-		clueData := map[string]interface{}{
-			"clue":   placement.Word.Clue,
-			"answer": placement.Word.Word,
-			"x":      placement.X,
-			"y":      placement.Y,
-			"length": len(placement.Word.Word),
+		clue := models.Clue{
+			Number:    numbers[[2]int{placement.X, placement.Y}],
+			Direction: models.DirectionAcross,
+			Text:      placement.Word.Clue,
+			Length:    len(placement.Word.Word),
+			X:         placement.X,
+			Y:         placement.Y,
+			Answer:    placement.Word.Word,
 		}
 
 		if placement.Vertical {
-			cluesDown[clueID] = clueData
+			clue.Direction = models.DirectionDown
+			cluesDown = append(cluesDown, clue)
 		} else {
-			cluesAcross[clueID] = clueData
+			cluesAcross = append(cluesAcross, clue)
 		}
 	}
 
-	// Set points and time based on difficulty
-	basePoints := 100
-	estimatedTime := 15
-	switch difficulty {
-	case "intermediate":
-		basePoints = 200
-		estimatedTime = 20
-	case "expert":
-		basePoints = 300
-		estimatedTime = 30
+	sortClues(cluesAcross)
+	sortClues(cluesDown)
+
+	// Build the grid and reveal the letters shown to the player before solving
+	gridData := models.BuildPuzzleGrid(cluesAcross, cluesDown)
+	for _, placement := range cw.Words {
+		for _, idx := range placement.Word.CharacterHints {
+			x, y := placement.X+idx, placement.Y
+			if placement.Vertical {
+				x, y = placement.X, placement.Y+idx
+			}
+			gridData.Reveal(x, y)
+		}
 	}
 
+	// Set points and time based on difficulty
+	defaults := models.DefaultsForDifficulty(difficulty)
+
 	// Extract metadata from words
 	var decade, region string
 	if len(originalWords) > 0 {
@@ -115,8 +116,8 @@ func (g *HipHopGenerator) convertToPuzzle(
 		GridData:      gridData,
 		CluesAcross:   cluesAcross,
 		CluesDown:     cluesDown,
-		EstimatedTime: estimatedTime,
-		BasePoints:    basePoints,
+		ParTime:       defaults.ParTime,
+		BasePoints:    defaults.BasePoints,
 		Decade:        decade,
 		Region:        region,
 	}
@@ -173,9 +174,37 @@ func generateDescription(words []HipHopWord, difficulty string) string {
 	return desc
 }
 
-// Helper function to convert placement to clue number
-func placementToClueNumber(placement *crossword.Placement) string {
-	// Use X and Y coordinates to generate a unique clue number
-	// This is a simple implementation - you might want to improve this
-	return strconv.Itoa(placement.Y*100 + placement.X)
+// sortClues orders clues by number
+func sortClues(clues models.ClueList) {
+	sort.Slice(clues, func(i, j int) bool {
+		return clues[i].Number < clues[j].Number
+	})
+}
+
+// numberPlacements assigns standard crossword numbers to word start cells.
+// Start cells are numbered in reading order (top to bottom, left to right),
+// and a cell that starts both an across and a down word gets one number.
+func numberPlacements(placements []crossword.Placement) map[[2]int]int {
+	starts := make([][2]int, 0, len(placements))
+	seen := make(map[[2]int]bool, len(placements))
+	for _, placement := range placements {
+		start := [2]int{placement.X, placement.Y}
+		if !seen[start] {
+			seen[start] = true
+			starts = append(starts, start)
+		}
+	}
+
+	sort.Slice(starts, func(i, j int) bool {
+		if starts[i][1] != starts[j][1] {
+			return starts[i][1] < starts[j][1]
+		}
+		return starts[i][0] < starts[j][0]
+	})
+
+	numbers := make(map[[2]int]int, len(starts))
+	for i, start := range starts {
+		numbers[start] = i + 1
+	}
+	return numbers
 }
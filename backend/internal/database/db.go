@@ -48,11 +48,19 @@ func AutoMigrate() error {
 		&models.Puzzle{},
 		&models.PuzzlePack{},
 		&models.PuzzleAttempt{},
+		&models.AttemptHint{},
+		&models.AttemptPause{},
+		&models.AttemptMember{},
+		&models.PuzzleBest{},
 		&models.Leaderboard{},
+		&models.StreakDay{},
 		&models.HipHopFact{},
 		&models.UserUnlockedFact{},
+		&models.Achievement{},
+		&models.UserAchievement{},
 		&models.Purchase{},
 		&models.MusicTrack{},
+		&models.IdempotencyKey{},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to auto-migrate: %w", err)
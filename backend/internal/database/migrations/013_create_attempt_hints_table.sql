@@ -0,0 +1,15 @@
+-- +migrate Up
+CREATE TABLE attempt_hints (
+    id SERIAL PRIMARY KEY,
+    attempt_id INTEGER NOT NULL REFERENCES puzzle_attempts(id) ON DELETE CASCADE,
+    hint_type VARCHAR(20) NOT NULL,
+    clue_id VARCHAR(20) NOT NULL,
+    direction VARCHAR(10) NOT NULL,
+    cells JSONB,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_hints_attempt ON attempt_hints(attempt_id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS attempt_hints CASCADE;
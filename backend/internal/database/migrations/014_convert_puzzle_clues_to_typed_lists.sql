@@ -0,0 +1,69 @@
+-- +migrate Up
+-- Clues used to be objects keyed by clue ID, they are now arrays of typed clues
+UPDATE puzzles SET clues_across = COALESCE((
+    SELECT jsonb_agg(jsonb_build_object(
+        'number', COALESCE((clue->>'number')::INTEGER, NULLIF(regexp_replace(key, '\D', '', 'g'), '')::INTEGER),
+        'direction', 'across',
+        'text', clue->>'clue',
+        'length', length(clue->>'answer'),
+        'x', (clue->>'x')::INTEGER,
+        'y', (clue->>'y')::INTEGER,
+        'answer', upper(clue->>'answer')
+    ))
+    FROM jsonb_each(clues_across) AS entry(key, clue)
+), '[]'::jsonb)
+WHERE jsonb_typeof(clues_across) = 'object';
+
+UPDATE puzzles SET clues_down = COALESCE((
+    SELECT jsonb_agg(jsonb_build_object(
+        'number', COALESCE((clue->>'number')::INTEGER, NULLIF(regexp_replace(key, '\D', '', 'g'), '')::INTEGER),
+        'direction', 'down',
+        'text', clue->>'clue',
+        'length', length(clue->>'answer'),
+        'x', (clue->>'x')::INTEGER,
+        'y', (clue->>'y')::INTEGER,
+        'answer', upper(clue->>'answer')
+    ))
+    FROM jsonb_each(clues_down) AS entry(key, clue)
+), '[]'::jsonb)
+WHERE jsonb_typeof(clues_down) = 'object';
+
+-- Old grids are rebuilt from the clues when the puzzle is loaded
+UPDATE puzzles SET grid_data = '{}'::jsonb WHERE jsonb_typeof(grid_data) <> 'object' OR NOT grid_data ? 'blocks';
+
+-- Hints now reference clues by number
+ALTER TABLE attempt_hints ADD COLUMN clue_number INTEGER;
+UPDATE attempt_hints SET clue_number = COALESCE(NULLIF(regexp_replace(clue_id, '\D', '', 'g'), '')::INTEGER, 0);
+ALTER TABLE attempt_hints ALTER COLUMN clue_number SET NOT NULL;
+ALTER TABLE attempt_hints DROP COLUMN clue_id;
+
+-- +migrate Down
+ALTER TABLE attempt_hints ADD COLUMN clue_id VARCHAR(20);
+UPDATE attempt_hints SET clue_id = clue_number::TEXT;
+ALTER TABLE attempt_hints DROP COLUMN clue_number;
+
+UPDATE puzzles SET clues_across = COALESCE((
+    SELECT jsonb_object_agg(clue->>'number', jsonb_build_object(
+        'number', clue->'number',
+        'clue', clue->'text',
+        'answer', clue->'answer',
+        'x', clue->'x',
+        'y', clue->'y',
+        'length', clue->'length'
+    ))
+    FROM jsonb_array_elements(clues_across) AS clue
+), '{}'::jsonb)
+WHERE jsonb_typeof(clues_across) = 'array';
+
+UPDATE puzzles SET clues_down = COALESCE((
+    SELECT jsonb_object_agg(clue->>'number', jsonb_build_object(
+        'number', clue->'number',
+        'clue', clue->'text',
+        'answer', clue->'answer',
+        'x', clue->'x',
+        'y', clue->'y',
+        'length', clue->'length'
+    ))
+    FROM jsonb_array_elements(clues_down) AS clue
+), '{}'::jsonb)
+WHERE jsonb_typeof(clues_down) = 'array';
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE user_profiles ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+-- +migrate Down
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS timezone;
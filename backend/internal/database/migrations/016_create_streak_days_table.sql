@@ -0,0 +1,24 @@
+-- +migrate Up
+CREATE TABLE streak_days (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    kind VARCHAR(10) NOT NULL,
+    puzzles_completed INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, date)
+);
+
+CREATE INDEX idx_streak_days_user_date ON streak_days(user_id, date DESC);
+
+ALTER TABLE user_profiles ADD COLUMN streak_freezes INTEGER DEFAULT 0;
+
+-- Seed history with the last played day of every profile
+INSERT INTO streak_days (user_id, date, kind, puzzles_completed)
+SELECT user_id, last_puzzle_date, 'played', 1
+FROM user_profiles
+WHERE last_puzzle_date IS NOT NULL;
+
+-- +migrate Down
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS streak_freezes;
+DROP TABLE IF EXISTS streak_days CASCADE;
@@ -0,0 +1,36 @@
+-- +migrate Up
+CREATE TABLE achievements (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) UNIQUE NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    icon_url VARCHAR(500),
+    rule_type VARCHAR(50) NOT NULL,
+    criteria JSONB NOT NULL DEFAULT '{}',
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_achievements_active ON achievements(is_active);
+
+CREATE TABLE user_achievements (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    achievement_id INTEGER NOT NULL REFERENCES achievements(id) ON DELETE CASCADE,
+    puzzle_id INTEGER REFERENCES puzzles(id) ON DELETE SET NULL,
+    awarded_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, achievement_id)
+);
+
+CREATE INDEX idx_user_achievements_user ON user_achievements(user_id);
+
+INSERT INTO achievements (code, name, description, rule_type, criteria) VALUES
+    ('first_puzzle', 'First Bars', 'Complete your first puzzle', 'puzzles_completed', '{"count": 1}'),
+    ('expert_10', 'Lyrical Miracle', 'Complete 10 expert puzzles', 'puzzles_completed', '{"count": 10, "difficulty": "expert"}'),
+    ('no_hints', 'No Ghostwriter', 'Solve a puzzle without using any hints', 'no_hint_solve', '{}'),
+    ('nyc_90s', 'Golden Era', 'Complete every 90s NYC puzzle', 'collection', '{"decade": "90s", "region": "NYC"}'),
+    ('streak_30', 'Thirty Deep', 'Reach a 30-day streak', 'streak', '{"days": 30}');
+
+-- +migrate Down
+DROP TABLE IF EXISTS user_achievements CASCADE;
+DROP TABLE IF EXISTS achievements CASCADE;
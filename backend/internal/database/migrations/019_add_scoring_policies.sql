@@ -0,0 +1,15 @@
+-- +migrate Up
+ALTER TABLE puzzles ADD COLUMN scoring_overrides JSONB;
+ALTER TABLE puzzle_packs ADD COLUMN scoring_overrides JSONB;
+
+ALTER TABLE puzzle_attempts ADD COLUMN scoring_policy_version VARCHAR(50);
+ALTER TABLE puzzle_attempts ADD COLUMN score_breakdown JSONB;
+
+-- Attempts completed so far were scored by the first default policy
+UPDATE puzzle_attempts SET scoring_policy_version = 'default-v1' WHERE is_completed = TRUE;
+
+-- +migrate Down
+ALTER TABLE puzzle_attempts DROP COLUMN IF EXISTS score_breakdown;
+ALTER TABLE puzzle_attempts DROP COLUMN IF EXISTS scoring_policy_version;
+ALTER TABLE puzzle_packs DROP COLUMN IF EXISTS scoring_overrides;
+ALTER TABLE puzzles DROP COLUMN IF EXISTS scoring_overrides;
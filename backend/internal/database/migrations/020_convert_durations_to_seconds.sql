@@ -0,0 +1,27 @@
+-- +migrate Up
+-- Estimated times were stored in minutes; par times are stored in seconds
+ALTER TABLE puzzles ADD COLUMN par_time_seconds INTEGER;
+ALTER TABLE puzzles ADD COLUMN par_time_samples INTEGER DEFAULT 0;
+UPDATE puzzles SET par_time_seconds = estimated_time * 60 WHERE estimated_time IS NOT NULL;
+ALTER TABLE puzzles DROP COLUMN estimated_time;
+
+-- Completion times were already seconds; the columns now say so
+ALTER TABLE puzzle_attempts RENAME COLUMN completion_time TO completion_seconds;
+ALTER TABLE leaderboards RENAME COLUMN average_completion_time TO average_completion_seconds;
+
+UPDATE puzzle_attempts
+SET score_breakdown = (score_breakdown - 'completion_time') || jsonb_build_object('completion_seconds', score_breakdown->'completion_time')
+WHERE score_breakdown ? 'completion_time';
+
+-- +migrate Down
+UPDATE puzzle_attempts
+SET score_breakdown = (score_breakdown - 'completion_seconds') || jsonb_build_object('completion_time', score_breakdown->'completion_seconds')
+WHERE score_breakdown ? 'completion_seconds';
+
+ALTER TABLE leaderboards RENAME COLUMN average_completion_seconds TO average_completion_time;
+ALTER TABLE puzzle_attempts RENAME COLUMN completion_seconds TO completion_time;
+
+ALTER TABLE puzzles ADD COLUMN estimated_time INTEGER;
+UPDATE puzzles SET estimated_time = par_time_seconds / 60 WHERE par_time_seconds IS NOT NULL;
+ALTER TABLE puzzles DROP COLUMN IF EXISTS par_time_samples;
+ALTER TABLE puzzles DROP COLUMN IF EXISTS par_time_seconds;
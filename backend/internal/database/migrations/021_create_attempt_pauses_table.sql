@@ -0,0 +1,20 @@
+-- +migrate Up
+CREATE TABLE attempt_pauses (
+    id SERIAL PRIMARY KEY,
+    attempt_id INTEGER NOT NULL REFERENCES puzzle_attempts(id) ON DELETE CASCADE,
+    paused_at TIMESTAMP NOT NULL,
+    resumed_at TIMESTAMP
+);
+
+CREATE INDEX idx_attempt_pauses_attempt_id ON attempt_pauses(attempt_id);
+
+-- Only one pause per attempt can be open at a time
+CREATE UNIQUE INDEX idx_attempt_pauses_open ON attempt_pauses(attempt_id) WHERE resumed_at IS NULL;
+
+ALTER TABLE puzzle_attempts ADD COLUMN client_completion_seconds INTEGER;
+ALTER TABLE puzzle_attempts ADD COLUMN time_mismatch BOOLEAN DEFAULT FALSE;
+
+-- +migrate Down
+ALTER TABLE puzzle_attempts DROP COLUMN IF EXISTS time_mismatch;
+ALTER TABLE puzzle_attempts DROP COLUMN IF EXISTS client_completion_seconds;
+DROP TABLE IF EXISTS attempt_pauses CASCADE;
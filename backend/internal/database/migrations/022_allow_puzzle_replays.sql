@@ -0,0 +1,35 @@
+-- +migrate Up
+-- Attempts become numbered runs; a user may replay a puzzle they solved
+ALTER TABLE puzzle_attempts DROP CONSTRAINT IF EXISTS puzzle_attempts_user_id_puzzle_id_key;
+DROP INDEX IF EXISTS idx_user_puzzle;
+
+ALTER TABLE puzzle_attempts ADD COLUMN run_number INTEGER NOT NULL DEFAULT 1;
+CREATE UNIQUE INDEX idx_user_puzzle_run ON puzzle_attempts(user_id, puzzle_id, run_number);
+
+CREATE TABLE puzzle_bests (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    puzzle_id INTEGER NOT NULL REFERENCES puzzles(id) ON DELETE CASCADE,
+    best_time_seconds INTEGER,
+    best_attempt_id INTEGER REFERENCES puzzle_attempts(id) ON DELETE SET NULL,
+    runs_completed INTEGER DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, puzzle_id)
+);
+
+-- Seed bests from the completions so far
+INSERT INTO puzzle_bests (user_id, puzzle_id, best_time_seconds, best_attempt_id, runs_completed)
+SELECT user_id, puzzle_id,
+    CASE WHEN accuracy_percentage = 100 THEN completion_seconds END,
+    CASE WHEN accuracy_percentage = 100 THEN id END,
+    1
+FROM puzzle_attempts
+WHERE is_completed = TRUE;
+
+-- +migrate Down
+DROP TABLE IF EXISTS puzzle_bests CASCADE;
+
+DELETE FROM puzzle_attempts WHERE run_number > 1;
+DROP INDEX IF EXISTS idx_user_puzzle_run;
+ALTER TABLE puzzle_attempts DROP COLUMN IF EXISTS run_number;
+ALTER TABLE puzzle_attempts ADD CONSTRAINT puzzle_attempts_user_id_puzzle_id_key UNIQUE (user_id, puzzle_id);
@@ -0,0 +1,10 @@
+-- +migrate Up
+ALTER TABLE puzzle_attempts ADD COLUMN version INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE puzzle_attempts ADD COLUMN cell_versions JSONB;
+
+-- Existing progress counts as the first version
+UPDATE puzzle_attempts SET version = 1 WHERE current_state IS NOT NULL AND current_state <> '{}'::jsonb;
+
+-- +migrate Down
+ALTER TABLE puzzle_attempts DROP COLUMN IF EXISTS cell_versions;
+ALTER TABLE puzzle_attempts DROP COLUMN IF EXISTS version;
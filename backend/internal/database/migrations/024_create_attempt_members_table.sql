@@ -0,0 +1,28 @@
+-- +migrate Up
+ALTER TABLE puzzle_attempts ADD COLUMN is_shared BOOLEAN DEFAULT FALSE;
+ALTER TABLE puzzle_attempts ADD COLUMN share_code VARCHAR(16);
+ALTER TABLE puzzle_attempts ADD COLUMN cell_authors JSONB;
+
+CREATE UNIQUE INDEX idx_puzzle_attempts_share_code ON puzzle_attempts(share_code);
+
+CREATE TABLE attempt_members (
+    id SERIAL PRIMARY KEY,
+    attempt_id INTEGER NOT NULL REFERENCES puzzle_attempts(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL,
+    correct_cells INTEGER DEFAULT 0,
+    points_earned INTEGER DEFAULT 0,
+    joined_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_attempt_members_attempt_id ON attempt_members(attempt_id);
+CREATE INDEX idx_attempt_members_user_id ON attempt_members(user_id);
+CREATE UNIQUE INDEX idx_attempt_member ON attempt_members(attempt_id, user_id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS attempt_members CASCADE;
+DROP INDEX IF EXISTS idx_puzzle_attempts_share_code;
+ALTER TABLE puzzle_attempts DROP COLUMN IF EXISTS cell_authors;
+ALTER TABLE puzzle_attempts DROP COLUMN IF EXISTS share_code;
+ALTER TABLE puzzle_attempts DROP COLUMN IF EXISTS is_shared;
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"hh_puzzle/internal/middleware"
+	"hh_puzzle/internal/models"
+	"hh_puzzle/internal/services"
+)
+
+// AchievementHandler handles achievement HTTP requests
+type AchievementHandler struct {
+	achievementService services.AchievementService
+}
+
+// NewAchievementHandler creates a new achievement handler
+func NewAchievementHandler(achievementService services.AchievementService) *AchievementHandler {
+	return &AchievementHandler{
+		achievementService: achievementService,
+	}
+}
+
+// CreateAchievementRequest represents the admin create achievement request
+type CreateAchievementRequest struct {
+	Code        string                     `json:"code" binding:"required"`
+	Name        string                     `json:"name" binding:"required"`
+	Description string                     `json:"description"`
+	IconURL     string                     `json:"icon_url"`
+	RuleType    string                     `json:"rule_type" binding:"required"` // puzzles_completed, no_hint_solve, collection, streak, total_points
+	Criteria    models.AchievementCriteria `json:"criteria"`
+}
+
+// GetAchievements returns every active achievement and whether the user earned it
+func (h *AchievementHandler) GetAchievements(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
+	achievements, err := h.achievementService.GetUserAchievements(claims.UserID)
+	if err != nil {
+		RespondInternalError(c, err.Error())
+		return
+	}
+
+	RespondSuccess(c, achievements, "")
+}
+
+// CreateAchievement adds a new badge definition
+func (h *AchievementHandler) CreateAchievement(c *gin.Context) {
+	var req CreateAchievementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondBadRequest(c, "Invalid request body")
+		return
+	}
+
+	achievement := &models.Achievement{
+		Code:        req.Code,
+		Name:        req.Name,
+		Description: req.Description,
+		IconURL:     req.IconURL,
+		RuleType:    req.RuleType,
+		Criteria:    req.Criteria,
+	}
+	if err := h.achievementService.CreateAchievement(achievement); err != nil {
+		RespondBadRequest(c, err.Error())
+		return
+	}
+
+	RespondCreated(c, achievement, "Achievement created successfully")
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"hh_puzzle/internal/services"
+)
+
+// AdminHandler handles admin and authoring HTTP requests.
+// Responses include full puzzle solutions.
+type AdminHandler struct {
+	puzzleService   services.PuzzleService
+	purchaseService services.PurchaseService
+	attemptService  services.AttemptService
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(
+	puzzleService services.PuzzleService,
+	purchaseService services.PurchaseService,
+	attemptService services.AttemptService,
+) *AdminHandler {
+	return &AdminHandler{
+		puzzleService:   puzzleService,
+		purchaseService: purchaseService,
+		attemptService:  attemptService,
+	}
+}
+
+// GetPuzzleByID returns a single puzzle including its solution
+func (h *AdminHandler) GetPuzzleByID(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid puzzle ID")
+		return
+	}
+
+	puzzle, err := h.puzzleService.GetPuzzleByID(uint(id))
+	if err != nil {
+		RespondNotFound(c, "Puzzle not found")
+		return
+	}
+
+	RespondSuccess(c, puzzle, "")
+}
+
+// RefundPurchase refunds a completed purchase with its payment provider
+func (h *AdminHandler) RefundPurchase(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid purchase ID")
+		return
+	}
+
+	purchase, err := h.purchaseService.RefundPurchase(uint(id))
+	if err != nil {
+		respondPurchaseError(c, err)
+		return
+	}
+
+	RespondSuccess(c, purchase, "Purchase refunded successfully")
+}
+
+// GetAttemptScore explains an attempt's score and recalculates it under the
+// policy_version query parameter, or the version it was scored with
+func (h *AdminHandler) GetAttemptScore(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid attempt ID")
+		return
+	}
+
+	explanation, err := h.attemptService.ExplainScore(uint(id), c.Query("policy_version"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAttemptNotFound):
+			RespondNotFound(c, "Attempt not found")
+		case errors.Is(err, services.ErrScoreNotRecorded):
+			RespondNotFound(c, "Attempt has no recorded score breakdown")
+		case errors.Is(err, services.ErrUnknownScoringPolicy):
+			RespondBadRequest(c, "Unknown scoring policy version")
+		default:
+			RespondInternalError(c, "Failed to explain score")
+		}
+		return
+	}
+
+	RespondSuccess(c, explanation, "")
+}
@@ -1,28 +1,37 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"hh_puzzle/internal/coop"
 	"hh_puzzle/internal/middleware"
+	"hh_puzzle/internal/models"
 	"hh_puzzle/internal/services"
 )
 
-// AttemptHandler handles puzzle attempt HTTP requests
+// AttemptHandler handles puzzle attempt HTTP requests. Changes to a shared
+// attempt are also published to the players connected to its room.
 type AttemptHandler struct {
 	attemptService services.AttemptService
+	hub            *coop.Hub
 }
 
 // NewAttemptHandler creates a new attempt handler
-func NewAttemptHandler(attemptService services.AttemptService) *AttemptHandler {
+func NewAttemptHandler(attemptService services.AttemptService, hub *coop.Hub) *AttemptHandler {
 	return &AttemptHandler{
 		attemptService: attemptService,
+		hub:            hub,
 	}
 }
 
 // StartAttemptRequest represents the start attempt request
 type StartAttemptRequest struct {
 	PuzzleID uint `json:"puzzle_id" binding:"required"`
+	Replay   bool `json:"replay"` // start a practice run of a completed puzzle
 }
 
 // UpdateProgressRequest represents the update progress request
@@ -30,10 +39,31 @@ type UpdateProgressRequest struct {
 	CurrentState map[string]interface{} `json:"current_state"`
 }
 
+// Progress sync conflict strategies
+const (
+	ProgressOnConflictReject = "reject" // a stale write gets a 409 with the server's changes
+	ProgressOnConflictMerge  = "merge"  // a stale write is merged cell by cell, last write wins
+)
+
+// SyncProgressRequest represents the delta progress request. The If-Match
+// header, when sent, takes precedence over BaseVersion.
+type SyncProgressRequest struct {
+	BaseVersion int               `json:"base_version"` // version the client's grid was last in sync with
+	Cells       map[string]string `json:"cells"`        // "x,y" -> letter, "" clears the cell
+	OnConflict  string            `json:"on_conflict"`  // reject (default) or merge
+}
+
 // SubmitAttemptRequest represents the submit attempt request
 type SubmitAttemptRequest struct {
-	CompletionTime int `json:"completion_time" binding:"required"`
-	HintsUsed      int `json:"hints_used"`
+	Entries        map[string]string `json:"entries"`            // "x,y" -> letter, defaults to saved progress
+	CompletionTime models.Seconds    `json:"completion_seconds"` // as measured by the app, checked against the server's timer
+}
+
+// UseHintRequest represents the hint request
+type UseHintRequest struct {
+	HintType   string `json:"hint_type" binding:"required"` // reveal_letter, reveal_word, check_word
+	ClueNumber int    `json:"clue_number" binding:"required"`
+	Direction  string `json:"direction" binding:"required"` // across, down
 }
 
 // StartAttempt starts a new puzzle attempt
@@ -50,17 +80,23 @@ func (h *AttemptHandler) StartAttempt(c *gin.Context) {
 		return
 	}
 
-	attempt, err := h.attemptService.StartAttempt(claims.UserID, req.PuzzleID)
+	attempt, err := h.attemptService.StartAttempt(claims.UserID, req.PuzzleID, req.Replay)
 	if err != nil {
-		RespondBadRequest(c, err.Error())
+		respondAttemptError(c, err)
 		return
 	}
 
-	RespondCreated(c, attempt, "Attempt started successfully")
+	RespondCreated(c, services.NewPlayerAttempt(attempt), "Attempt started successfully")
 }
 
 // UpdateProgress updates the progress of an attempt
 func (h *AttemptHandler) UpdateProgress(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
@@ -74,16 +110,148 @@ func (h *AttemptHandler) UpdateProgress(c *gin.Context) {
 		return
 	}
 
-	if err := h.attemptService.UpdateProgress(uint(id), req.CurrentState); err != nil {
-		RespondBadRequest(c, err.Error())
+	delta, err := h.attemptService.UpdateProgress(claims.UserID, uint(id), req.CurrentState)
+	if err != nil {
+		respondAttemptError(c, err)
+		return
+	}
+
+	h.hub.Publish(uint(id), coop.CellsMessage(claims.UserID, delta))
+	setVersionETag(c, delta.Version)
+	RespondSuccess(c, delta, "Progress updated successfully")
+}
+
+// SyncProgress applies the cells changed on the client since its base version
+// and returns every change since then
+func (h *AttemptHandler) SyncProgress(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid attempt ID")
+		return
+	}
+
+	var req SyncProgressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondBadRequest(c, "Invalid request body")
+		return
+	}
+
+	if header := c.GetHeader("If-Match"); header != "" {
+		version, ok := parseVersionETag(header)
+		if !ok {
+			RespondBadRequest(c, "Invalid If-Match header")
+			return
+		}
+		req.BaseVersion = version
+	}
+
+	var rejectStale bool
+	switch req.OnConflict {
+	case "", ProgressOnConflictReject:
+		rejectStale = true
+	case ProgressOnConflictMerge:
+	default:
+		RespondBadRequest(c, "on_conflict must be reject or merge")
+		return
+	}
+
+	delta, err := h.attemptService.SyncProgress(claims.UserID, uint(id), req.BaseVersion, req.Cells, rejectStale)
+	if err != nil {
+		if delta != nil {
+			setVersionETag(c, delta.Version)
+		}
+		respondProgressError(c, err, delta)
 		return
 	}
 
-	RespondSuccess(c, nil, "Progress updated successfully")
+	h.hub.Publish(uint(id), coop.CellsMessage(claims.UserID, delta))
+	setVersionETag(c, delta.Version)
+	RespondSuccess(c, delta, "Progress updated successfully")
+}
+
+// GetProgress returns the cells changed after the since version, or the
+// whole grid when since is omitted
+func (h *AttemptHandler) GetProgress(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid attempt ID")
+		return
+	}
+
+	since, err := strconv.Atoi(c.DefaultQuery("since", "0"))
+	if err != nil || since < 0 {
+		RespondBadRequest(c, "Invalid since version")
+		return
+	}
+
+	delta, err := h.attemptService.GetProgress(claims.UserID, uint(id), since)
+	if err != nil {
+		respondAttemptError(c, err)
+		return
+	}
+
+	setVersionETag(c, delta.Version)
+	if version, ok := parseVersionETag(c.GetHeader("If-None-Match")); ok && version == delta.Version {
+		c.Status(304)
+		return
+	}
+
+	RespondSuccess(c, delta, "")
+}
+
+// UseHint reveals or checks part of the answer for an attempt
+func (h *AttemptHandler) UseHint(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid attempt ID")
+		return
+	}
+
+	var req UseHintRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondBadRequest(c, "Invalid request body")
+		return
+	}
+
+	result, err := h.attemptService.UseHint(claims.UserID, uint(id), req.HintType, req.ClueNumber, req.Direction)
+	if err != nil {
+		respondAttemptError(c, err)
+		return
+	}
+
+	h.hub.Publish(uint(id), coop.HintMessage(claims.UserID, uint(id), result))
+	RespondSuccess(c, result, "Hint applied successfully")
 }
 
 // SubmitAttempt submits a completed attempt
 func (h *AttemptHandler) SubmitAttempt(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
@@ -97,15 +265,88 @@ func (h *AttemptHandler) SubmitAttempt(c *gin.Context) {
 		return
 	}
 
-	result, err := h.attemptService.SubmitAttempt(uint(id), req.CompletionTime, req.HintsUsed)
+	result, err := h.attemptService.SubmitAttempt(claims.UserID, uint(id), req.Entries, req.CompletionTime)
 	if err != nil {
-		RespondBadRequest(c, err.Error())
+		respondAttemptError(c, err)
 		return
 	}
 
+	h.hub.Publish(uint(id), coop.CompletedMessage(claims.UserID, result))
 	RespondSuccess(c, result, "Attempt submitted successfully")
 }
 
+// PauseAttempt stops the attempt's timer while the app is in the background
+func (h *AttemptHandler) PauseAttempt(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid attempt ID")
+		return
+	}
+
+	timer, err := h.attemptService.PauseAttempt(claims.UserID, uint(id))
+	if err != nil {
+		respondAttemptError(c, err)
+		return
+	}
+
+	RespondSuccess(c, timer, "Attempt paused")
+}
+
+// ResumeAttempt restarts the timer of a paused attempt
+func (h *AttemptHandler) ResumeAttempt(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid attempt ID")
+		return
+	}
+
+	timer, err := h.attemptService.ResumeAttempt(claims.UserID, uint(id))
+	if err != nil {
+		respondAttemptError(c, err)
+		return
+	}
+
+	RespondSuccess(c, timer, "Attempt resumed")
+}
+
+// GetAttemptTimer returns the server's timer for an attempt
+func (h *AttemptHandler) GetAttemptTimer(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid attempt ID")
+		return
+	}
+
+	timer, err := h.attemptService.GetAttemptTimer(claims.UserID, uint(id))
+	if err != nil {
+		respondAttemptError(c, err)
+		return
+	}
+
+	RespondSuccess(c, timer, "")
+}
+
 // GetAttempts returns all attempts for the current user
 func (h *AttemptHandler) GetAttempts(c *gin.Context) {
 	claims, ok := middleware.GetUserFromContext(c)
@@ -120,11 +361,34 @@ func (h *AttemptHandler) GetAttempts(c *gin.Context) {
 		return
 	}
 
-	RespondSuccess(c, attempts, "")
+	RespondSuccess(c, services.NewPlayerAttempts(attempts), "")
+}
+
+// GetBestTimes returns the current user's best time on each puzzle
+func (h *AttemptHandler) GetBestTimes(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
+	bests, err := h.attemptService.GetBestTimes(claims.UserID)
+	if err != nil {
+		RespondInternalError(c, err.Error())
+		return
+	}
+
+	RespondSuccess(c, bests, "")
 }
 
 // GetAttemptByID returns a single attempt by ID
 func (h *AttemptHandler) GetAttemptByID(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
@@ -132,11 +396,63 @@ func (h *AttemptHandler) GetAttemptByID(c *gin.Context) {
 		return
 	}
 
-	attempt, err := h.attemptService.GetAttemptByID(uint(id))
+	attempt, err := h.attemptService.GetAttemptByID(claims.UserID, uint(id))
 	if err != nil {
-		RespondNotFound(c, "Attempt not found")
+		respondAttemptError(c, err)
 		return
 	}
 
-	RespondSuccess(c, attempt, "")
+	RespondSuccess(c, services.NewPlayerAttempt(attempt), "")
+}
+
+// setVersionETag sets the ETag header to an attempt's state version
+func setVersionETag(c *gin.Context, version int) {
+	c.Header("ETag", fmt.Sprintf("%q", strconv.Itoa(version)))
+}
+
+// parseVersionETag reads a state version from an ETag, quoted or not
+func parseVersionETag(etag string) (int, bool) {
+	etag = strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+	version, err := strconv.Atoi(strings.Trim(etag, `"`))
+	if err != nil || version < 0 {
+		return 0, false
+	}
+	return version, true
+}
+
+// respondProgressError maps progress sync errors to HTTP responses, sending the
+// server's changes with a conflict
+func respondProgressError(c *gin.Context, err error, delta *services.ProgressDelta) {
+	switch {
+	case errors.Is(err, services.ErrProgressConflict):
+		RespondConflict(c, "Progress changed on another device", "PROGRESS_CONFLICT", delta)
+	default:
+		respondAttemptError(c, err)
+	}
+}
+
+// respondAttemptError maps attempt service errors to HTTP responses
+func respondAttemptError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrAttemptNotFound):
+		RespondNotFound(c, "Attempt not found")
+	case errors.Is(err, services.ErrAttemptForbidden):
+		RespondForbidden(c, "You do not have access to this attempt")
+	case errors.Is(err, services.ErrAttemptCompleted):
+		RespondError(c, 409, "Attempt already completed", "ATTEMPT_COMPLETED")
+	case errors.Is(err, services.ErrPuzzleCompleted):
+		RespondError(c, 409, "Puzzle already completed, start a replay to play it again", "PUZZLE_COMPLETED")
+	case errors.Is(err, services.ErrProgressConflict):
+		RespondError(c, 409, "Progress changed on another device, reload and try again", "PROGRESS_CONFLICT")
+	case errors.Is(err, services.ErrInvalidCell):
+		RespondError(c, 400, err.Error(), "INVALID_CELL")
+	case errors.Is(err, services.ErrAttemptNotPaused):
+		RespondError(c, 409, "Attempt is not paused", "ATTEMPT_NOT_PAUSED")
+	case errors.Is(err, services.ErrAttemptPaused):
+		RespondError(c, 409, "Attempt is paused, resume it to keep playing", "ATTEMPT_PAUSED")
+	case errors.Is(err, services.ErrPuzzleLocked):
+		RespondError(c, 402, "Puzzle requires a purchase or subscription", "PAYMENT_REQUIRED")
+	default:
+		RespondBadRequest(c, err.Error())
+	}
 }
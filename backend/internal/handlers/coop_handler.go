@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+	"hh_puzzle/internal/coop"
+	"hh_puzzle/internal/middleware"
+	"hh_puzzle/internal/services"
+)
+
+// Co-op message types sent by players
+const (
+	CoopMessageFill   = "fill"   // fill or clear cells
+	CoopMessageCursor = "cursor" // move the player's cursor
+	CoopMessageHint   = "hint"   // use a hint for the team
+	CoopMessagePing   = "ping"   // keep an idle connection open
+)
+
+// CoopHandler handles shared attempts and their WebSocket rooms
+type CoopHandler struct {
+	coopService    services.CoopService
+	attemptService services.AttemptService
+	hub            *coop.Hub
+}
+
+// NewCoopHandler creates a new co-op handler
+func NewCoopHandler(coopService services.CoopService, attemptService services.AttemptService, hub *coop.Hub) *CoopHandler {
+	return &CoopHandler{
+		coopService:    coopService,
+		attemptService: attemptService,
+		hub:            hub,
+	}
+}
+
+// JoinAttemptRequest represents the join shared attempt request
+type JoinAttemptRequest struct {
+	ShareCode string `json:"share_code" binding:"required"`
+}
+
+// CoopClientMessage is a message a player sends over the co-op WebSocket
+type CoopClientMessage struct {
+	Type string `json:"type"` // fill, cursor, hint, ping
+
+	// fill
+	BaseVersion int               `json:"base_version"` // version the player's grid was in sync with
+	Cells       map[string]string `json:"cells"`        // "x,y" -> letter, "" clears the cell
+
+	// cursor and hint
+	X          int    `json:"x"`
+	Y          int    `json:"y"`
+	Direction  string `json:"direction"` // across, down
+	HintType   string `json:"hint_type"`
+	ClueNumber int    `json:"clue_number"`
+}
+
+// ShareAttempt shares the user's attempt and returns the code friends join it with
+func (h *CoopHandler) ShareAttempt(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid attempt ID")
+		return
+	}
+
+	shared, err := h.coopService.ShareAttempt(claims.UserID, uint(id))
+	if err != nil {
+		respondCoopError(c, err)
+		return
+	}
+
+	RespondSuccess(c, shared, "Attempt shared successfully")
+}
+
+// JoinAttempt adds the user to the attempt shared with a code
+func (h *CoopHandler) JoinAttempt(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
+	var req JoinAttemptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondBadRequest(c, "Invalid request body")
+		return
+	}
+
+	shared, err := h.coopService.JoinAttempt(claims.UserID, req.ShareCode)
+	if err != nil {
+		respondCoopError(c, err)
+		return
+	}
+
+	RespondSuccess(c, shared, "Joined shared attempt")
+}
+
+// GetSharedAttempt returns the members of a shared attempt and how its points are split
+func (h *CoopHandler) GetSharedAttempt(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid attempt ID")
+		return
+	}
+
+	shared, err := h.coopService.GetSharedAttempt(claims.UserID, uint(id))
+	if err != nil {
+		respondCoopError(c, err)
+		return
+	}
+
+	RespondSuccess(c, shared, "")
+}
+
+// Connect upgrades to a WebSocket in the shared attempt's room. The welcome
+// message carries the progress since the since version, so a reconnecting
+// player only receives what they missed.
+func (h *CoopHandler) Connect(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid attempt ID")
+		return
+	}
+
+	since, err := strconv.Atoi(c.DefaultQuery("since", "0"))
+	if err != nil || since < 0 {
+		RespondBadRequest(c, "Invalid since version")
+		return
+	}
+
+	shared, err := h.coopService.GetSharedAttempt(claims.UserID, uint(id))
+	if err != nil {
+		respondCoopError(c, err)
+		return
+	}
+
+	progress, err := h.attemptService.GetProgress(claims.UserID, uint(id), since)
+	if err != nil {
+		respondAttemptError(c, err)
+		return
+	}
+
+	welcome := coop.Message{Shared: shared, Progress: progress}
+	server := websocket.Server{
+		Handler: func(conn *websocket.Conn) {
+			client := h.hub.Connect(uint(id), claims.UserID, conn, welcome)
+			defer h.hub.Disconnect(client)
+			h.serve(client)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// serve handles a player's messages until the connection closes
+func (h *CoopHandler) serve(client *coop.Client) {
+	for {
+		var msg CoopClientMessage
+		if err := client.Receive(&msg); err != nil {
+			if errors.Is(err, coop.ErrInvalidMessage) {
+				h.hub.Reply(client, coop.ErrorMessage("BAD_REQUEST", "Invalid message"))
+				continue
+			}
+			return
+		}
+
+		switch msg.Type {
+		case CoopMessageFill:
+			delta, err := h.attemptService.SyncProgress(client.UserID, client.AttemptID, msg.BaseVersion, msg.Cells, false)
+			if err != nil {
+				h.hub.Reply(client, coopErrorMessage(err))
+				continue
+			}
+			h.hub.Publish(client.AttemptID, coop.CellsMessage(client.UserID, delta))
+
+		case CoopMessageCursor:
+			h.hub.Broadcast(client, coop.Message{
+				Type:   coop.MessageCursor,
+				UserID: client.UserID,
+				Cursor: &coop.Cursor{X: msg.X, Y: msg.Y, Direction: msg.Direction},
+			})
+
+		case CoopMessageHint:
+			result, err := h.attemptService.UseHint(client.UserID, client.AttemptID, msg.HintType, msg.ClueNumber, msg.Direction)
+			if err != nil {
+				h.hub.Reply(client, coopErrorMessage(err))
+				continue
+			}
+			h.hub.Publish(client.AttemptID, coop.HintMessage(client.UserID, client.AttemptID, result))
+
+		case CoopMessagePing:
+
+		default:
+			h.hub.Reply(client, coop.ErrorMessage("BAD_REQUEST", "Unknown message type"))
+		}
+	}
+}
+
+// coopErrorMessage maps attempt service errors to WebSocket error messages
+func coopErrorMessage(err error) coop.Message {
+	switch {
+	case errors.Is(err, services.ErrAttemptCompleted):
+		return coop.ErrorMessage("ATTEMPT_COMPLETED", "Attempt already completed")
+	case errors.Is(err, services.ErrAttemptPaused):
+		return coop.ErrorMessage("ATTEMPT_PAUSED", "Attempt is paused, resume it to keep playing")
+	case errors.Is(err, services.ErrAttemptForbidden):
+		return coop.ErrorMessage("FORBIDDEN", "You do not have access to this attempt")
+	case errors.Is(err, services.ErrProgressConflict):
+		return coop.ErrorMessage("PROGRESS_CONFLICT", "Progress changed too often, try again")
+	case errors.Is(err, services.ErrInvalidCell):
+		return coop.ErrorMessage("INVALID_CELL", err.Error())
+	default:
+		return coop.ErrorMessage("BAD_REQUEST", err.Error())
+	}
+}
+
+// respondCoopError maps co-op service errors to HTTP responses
+func respondCoopError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrShareCodeInvalid):
+		RespondNotFound(c, "No shared attempt with this code")
+	case errors.Is(err, services.ErrAttemptNotShared):
+		RespondError(c, 409, "Attempt is not shared", "ATTEMPT_NOT_SHARED")
+	case errors.Is(err, services.ErrAttemptFull):
+		RespondError(c, 409, "Shared attempt is full", "ATTEMPT_FULL")
+	case errors.Is(err, services.ErrPuzzleCompleted):
+		RespondError(c, 409, "Puzzle already completed, replays can only be played alone", "PUZZLE_COMPLETED")
+	default:
+		respondAttemptError(c, err)
+	}
+}
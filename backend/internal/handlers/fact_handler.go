@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"hh_puzzle/internal/middleware"
+	"hh_puzzle/internal/services"
+)
+
+// FactHandler handles hip-hop fact HTTP requests
+type FactHandler struct {
+	factService services.FactService
+}
+
+// NewFactHandler creates a new fact handler
+func NewFactHandler(factService services.FactService) *FactHandler {
+	return &FactHandler{
+		factService: factService,
+	}
+}
+
+// GetFacts returns every fact, with content only for unlocked ones
+func (h *FactHandler) GetFacts(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
+	facts, err := h.factService.GetFacts(claims.UserID)
+	if err != nil {
+		RespondInternalError(c, err.Error())
+		return
+	}
+
+	RespondSuccess(c, facts, "")
+}
+
+// GetFactByID returns a single unlocked fact
+func (h *FactHandler) GetFactByID(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid fact ID")
+		return
+	}
+
+	fact, err := h.factService.GetFact(claims.UserID, uint(id))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrFactNotFound):
+			RespondNotFound(c, "Fact not found")
+		case errors.Is(err, services.ErrFactLocked):
+			RespondError(c, 403, "Fact is locked", "FACT_LOCKED")
+		default:
+			RespondInternalError(c, err.Error())
+		}
+		return
+	}
+
+	RespondSuccess(c, fact, "")
+}
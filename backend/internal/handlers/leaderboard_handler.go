@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"hh_puzzle/internal/middleware"
+	"hh_puzzle/internal/services"
+)
+
+// LeaderboardHandler handles leaderboard HTTP requests
+type LeaderboardHandler struct {
+	leaderboardService services.LeaderboardService
+}
+
+// NewLeaderboardHandler creates a new leaderboard handler
+func NewLeaderboardHandler(leaderboardService services.LeaderboardService) *LeaderboardHandler {
+	return &LeaderboardHandler{
+		leaderboardService: leaderboardService,
+	}
+}
+
+// GetWeekly returns the weekly leaderboard. The optional week query
+// parameter (YYYY-MM-DD) selects the week containing that date.
+func (h *LeaderboardHandler) GetWeekly(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
+	date := time.Now()
+	if week := c.Query("week"); week != "" {
+		parsed, err := time.Parse("2006-01-02", week)
+		if err != nil {
+			RespondBadRequest(c, "Invalid week, expected YYYY-MM-DD")
+			return
+		}
+		date = parsed
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+
+	leaderboard, servicePagination, err := h.leaderboardService.GetWeeklyLeaderboard(claims.UserID, date, page, perPage)
+	if err != nil {
+		RespondInternalError(c, err.Error())
+		return
+	}
+
+	RespondPaginated(c, leaderboard, toPagination(servicePagination))
+}
+
+// GetAllTime returns the all-time leaderboard
+func (h *LeaderboardHandler) GetAllTime(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+
+	leaderboard, servicePagination, err := h.leaderboardService.GetAllTimeLeaderboard(claims.UserID, page, perPage)
+	if err != nil {
+		RespondInternalError(c, err.Error())
+		return
+	}
+
+	RespondPaginated(c, leaderboard, toPagination(servicePagination))
+}
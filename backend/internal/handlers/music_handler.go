@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"hh_puzzle/internal/middleware"
+	"hh_puzzle/internal/models"
+	"hh_puzzle/internal/services"
+)
+
+// MusicHandler handles background music HTTP requests
+type MusicHandler struct {
+	musicService services.MusicService
+}
+
+// NewMusicHandler creates a new music handler
+func NewMusicHandler(musicService services.MusicService) *MusicHandler {
+	return &MusicHandler{
+		musicService: musicService,
+	}
+}
+
+// MusicTrackRequest represents the admin create and update track request
+type MusicTrackRequest struct {
+	Title           string `json:"title" binding:"required"`
+	Artist          string `json:"artist"`
+	FileURL         string `json:"file_url" binding:"required"`
+	FileSizeKB      *int   `json:"file_size_kb"`
+	DurationSeconds *int   `json:"duration_seconds"`
+	Genre           string `json:"genre"`
+	Mood            string `json:"mood"` // chill, energetic, focused
+	BPM             *int   `json:"bpm"`
+	IsActive        *bool  `json:"is_active"` // defaults to true
+}
+
+// GetTracks returns tracks filtered by mood and active flag
+func (h *MusicHandler) GetTracks(c *gin.Context) {
+	mood := c.Query("mood")
+
+	active, err := strconv.ParseBool(c.DefaultQuery("active", "true"))
+	if err != nil {
+		RespondBadRequest(c, "Invalid active filter")
+		return
+	}
+
+	tracks, err := h.musicService.GetTracks(mood, &active)
+	if err != nil {
+		RespondInternalError(c, err.Error())
+		return
+	}
+
+	RespondSuccess(c, tracks, "")
+}
+
+// GetPlaylist returns a shuffled queue of tracks for a puzzle
+func (h *MusicHandler) GetPlaylist(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
+	idStr := c.Param("puzzle_id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid puzzle ID")
+		return
+	}
+
+	playlist, err := h.musicService.GetPlaylist(claims.UserID, uint(id))
+	if err != nil {
+		RespondNotFound(c, "Puzzle not found")
+		return
+	}
+
+	RespondSuccess(c, playlist, "")
+}
+
+// ReportPlay records that a track was played
+func (h *MusicHandler) ReportPlay(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid track ID")
+		return
+	}
+
+	if err := h.musicService.ReportPlay(uint(id)); err != nil {
+		RespondNotFound(c, "Track not found")
+		return
+	}
+
+	RespondSuccess(c, nil, "Play recorded successfully")
+}
+
+// CreateTrack adds a track to the catalog
+func (h *MusicHandler) CreateTrack(c *gin.Context) {
+	var req MusicTrackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondBadRequest(c, "Invalid request body")
+		return
+	}
+
+	track := req.toTrack()
+	if err := h.musicService.CreateTrack(track); err != nil {
+		RespondBadRequest(c, err.Error())
+		return
+	}
+
+	RespondCreated(c, track, "Track created successfully")
+}
+
+// UpdateTrack replaces a track's catalog details
+func (h *MusicHandler) UpdateTrack(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid track ID")
+		return
+	}
+
+	var req MusicTrackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondBadRequest(c, "Invalid request body")
+		return
+	}
+
+	track, err := h.musicService.UpdateTrack(uint(id), req.toTrack())
+	if err != nil {
+		if err.Error() == "track not found" {
+			RespondNotFound(c, "Track not found")
+			return
+		}
+		RespondBadRequest(c, err.Error())
+		return
+	}
+
+	RespondSuccess(c, track, "Track updated successfully")
+}
+
+// DeleteTrack removes a track from the catalog
+func (h *MusicHandler) DeleteTrack(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid track ID")
+		return
+	}
+
+	if err := h.musicService.DeleteTrack(uint(id)); err != nil {
+		RespondNotFound(c, "Track not found")
+		return
+	}
+
+	RespondSuccess(c, nil, "Track deleted successfully")
+}
+
+// toTrack builds a track model from the request
+func (r MusicTrackRequest) toTrack() *models.MusicTrack {
+	track := &models.MusicTrack{
+		Title:           r.Title,
+		Artist:          r.Artist,
+		FileURL:         r.FileURL,
+		FileSizeKB:      r.FileSizeKB,
+		DurationSeconds: r.DurationSeconds,
+		Genre:           r.Genre,
+		Mood:            r.Mood,
+		BPM:             r.BPM,
+		IsActive:        true,
+	}
+	if track.Genre == "" {
+		track.Genre = "hip-hop"
+	}
+	if r.IsActive != nil {
+		track.IsActive = *r.IsActive
+	}
+	return track
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"hh_puzzle/internal/middleware"
+	"hh_puzzle/internal/payments"
+	"hh_puzzle/internal/services"
+)
+
+// PurchaseHandler handles puzzle pack purchase HTTP requests
+type PurchaseHandler struct {
+	purchaseService services.PurchaseService
+}
+
+// NewPurchaseHandler creates a new purchase handler
+func NewPurchaseHandler(purchaseService services.PurchaseService) *PurchaseHandler {
+	return &PurchaseHandler{
+		purchaseService: purchaseService,
+	}
+}
+
+// StartPurchaseRequest represents the start purchase request
+type StartPurchaseRequest struct {
+	PuzzlePackID uint   `json:"puzzle_pack_id" binding:"required"`
+	Provider     string `json:"provider"` // defaults to the configured provider
+}
+
+// StartPurchase creates a pending purchase and returns its checkout URL
+func (h *PurchaseHandler) StartPurchase(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
+	var req StartPurchaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondBadRequest(c, "Invalid request body")
+		return
+	}
+
+	result, err := h.purchaseService.StartPurchase(claims.UserID, req.PuzzlePackID, req.Provider)
+	if err != nil {
+		respondPurchaseError(c, err)
+		return
+	}
+
+	RespondCreated(c, result, "Checkout started successfully")
+}
+
+// GetPurchases returns the user's purchase history
+func (h *PurchaseHandler) GetPurchases(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
+	purchases, err := h.purchaseService.GetUserPurchases(claims.UserID)
+	if err != nil {
+		RespondInternalError(c, err.Error())
+		return
+	}
+
+	RespondSuccess(c, purchases, "")
+}
+
+// HandleWebhook applies a payment status update sent by a provider
+func (h *PurchaseHandler) HandleWebhook(c *gin.Context) {
+	payload, err := c.GetRawData()
+	if err != nil {
+		RespondBadRequest(c, "Invalid request body")
+		return
+	}
+
+	purchase, err := h.purchaseService.HandleWebhook(c.Param("provider"), payload, c.GetHeader("X-Payment-Signature"))
+	if err != nil {
+		respondPurchaseError(c, err)
+		return
+	}
+
+	RespondSuccess(c, purchase, "Webhook processed successfully")
+}
+
+// respondPurchaseError maps purchase service errors to HTTP responses
+func respondPurchaseError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrPackNotFound):
+		RespondNotFound(c, "Puzzle pack not found")
+	case errors.Is(err, services.ErrPurchaseNotFound):
+		RespondNotFound(c, "Purchase not found")
+	case errors.Is(err, services.ErrUnknownPaymentProvider):
+		RespondNotFound(c, "Unknown payment provider")
+	case errors.Is(err, services.ErrPackAlreadyOwned):
+		RespondError(c, 409, "Puzzle pack already owned", "ALREADY_OWNED")
+	case errors.Is(err, services.ErrInvalidPurchaseTransition):
+		RespondError(c, 409, err.Error(), "INVALID_TRANSITION")
+	case errors.Is(err, payments.ErrInvalidSignature):
+		RespondUnauthorized(c, "Invalid webhook signature")
+	case errors.Is(err, services.ErrSubscriptionNotFound):
+		RespondBadRequest(c, "Purchase is not a subscription")
+	case errors.Is(err, services.ErrSubscriptionConflict):
+		RespondError(c, 409, err.Error(), "SUBSCRIPTION_CONFLICT")
+	case errors.Is(err, services.ErrPackNotForSale):
+		RespondBadRequest(c, "Puzzle pack is free")
+	default:
+		RespondBadRequest(c, err.Error())
+	}
+}
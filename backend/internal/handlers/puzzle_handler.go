@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"hh_puzzle/internal/middleware"
 	"hh_puzzle/internal/services"
 )
 
@@ -19,8 +21,15 @@ func NewPuzzleHandler(puzzleService services.PuzzleService) *PuzzleHandler {
 	}
 }
 
-// GetPuzzles returns a list of puzzles with filters
+// GetPuzzles returns a list of puzzles with filters. Puzzles the user has not
+// unlocked are listed as previews.
 func (h *PuzzleHandler) GetPuzzles(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
 	// Parse query parameters
 	difficulty := c.Query("difficulty")
 	decade := c.Query("decade")
@@ -43,6 +52,12 @@ func (h *PuzzleHandler) GetPuzzles(c *gin.Context) {
 		return
 	}
 
+	views, err := h.puzzleService.GetPuzzleViews(claims.UserID, puzzles)
+	if err != nil {
+		RespondInternalError(c, err.Error())
+		return
+	}
+
 	// Convert service pagination to handler pagination
 	pagination := Pagination{
 		Page:       servicePagination.Page,
@@ -51,11 +66,17 @@ func (h *PuzzleHandler) GetPuzzles(c *gin.Context) {
 		TotalPages: servicePagination.TotalPages,
 	}
 
-	RespondPaginated(c, puzzles, pagination)
+	RespondPaginated(c, views, pagination)
 }
 
 // GetPuzzleByID returns a single puzzle by ID
 func (h *PuzzleHandler) GetPuzzleByID(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
@@ -63,29 +84,42 @@ func (h *PuzzleHandler) GetPuzzleByID(c *gin.Context) {
 		return
 	}
 
-	puzzle, err := h.puzzleService.GetPuzzleByID(uint(id))
+	puzzle, err := h.puzzleService.GetPuzzleForUser(claims.UserID, uint(id))
+	if errors.Is(err, services.ErrPuzzleLocked) {
+		RespondPaymentRequired(c, "Puzzle requires a purchase or subscription", services.NewPuzzlePreview(puzzle))
+		return
+	}
 	if err != nil {
 		RespondNotFound(c, "Puzzle not found")
 		return
 	}
 
-	RespondSuccess(c, puzzle, "")
+	RespondSuccess(c, services.NewPlayerPuzzle(puzzle), "")
 }
 
 // GetDailyChallenge returns today's daily challenge
 func (h *PuzzleHandler) GetDailyChallenge(c *gin.Context) {
-	puzzle, err := h.puzzleService.GetDailyChallenge()
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
+	puzzle, err := h.puzzleService.GetDailyChallenge(claims.UserID)
 	if err != nil {
 		RespondNotFound(c, "No daily challenge available")
 		return
 	}
 
-	RespondSuccess(c, puzzle, "")
+	RespondSuccess(c, services.NewPlayerPuzzle(puzzle), "")
 }
 
 // GetPuzzlePacks returns all available puzzle packs
 func (h *PuzzleHandler) GetPuzzlePacks(c *gin.Context) {
-	packs, err := h.puzzleService.GetAvailablePacks()
+	categoryType := c.Query("category_type")
+	categoryValue := c.Query("category_value")
+
+	packs, err := h.puzzleService.GetAvailablePacks(categoryType, categoryValue)
 	if err != nil {
 		RespondInternalError(c, err.Error())
 		return
@@ -96,6 +130,12 @@ func (h *PuzzleHandler) GetPuzzlePacks(c *gin.Context) {
 
 // GetPuzzlePackByID returns a single puzzle pack by ID
 func (h *PuzzleHandler) GetPuzzlePackByID(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
@@ -103,7 +143,7 @@ func (h *PuzzleHandler) GetPuzzlePackByID(c *gin.Context) {
 		return
 	}
 
-	pack, err := h.puzzleService.GetPuzzlePack(uint(id))
+	pack, err := h.puzzleService.GetPuzzlePack(claims.UserID, uint(id))
 	if err != nil {
 		RespondNotFound(c, "Puzzle pack not found")
 		return
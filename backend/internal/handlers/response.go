@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"github.com/gin-gonic/gin"
+	"hh_puzzle/internal/services"
 )
 
 // SuccessResponse represents a successful API response
@@ -13,9 +14,10 @@ type SuccessResponse struct {
 
 // ErrorResponse represents an error API response
 type ErrorResponse struct {
-	Success bool   `json:"success"`
-	Error   string `json:"error"`
-	Code    string `json:"code,omitempty"`
+	Success bool        `json:"success"`
+	Error   string      `json:"error"`
+	Code    string      `json:"code,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
 }
 
 // PaginatedResponse represents a paginated API response
@@ -75,6 +77,26 @@ func RespondForbidden(c *gin.Context, message string) {
 	RespondError(c, 403, message, "FORBIDDEN")
 }
 
+// RespondPaymentRequired sends a 402 Payment Required response with a preview of the locked content
+func RespondPaymentRequired(c *gin.Context, message string, preview interface{}) {
+	c.JSON(402, ErrorResponse{
+		Success: false,
+		Error:   message,
+		Code:    "PAYMENT_REQUIRED",
+		Data:    preview,
+	})
+}
+
+// RespondConflict sends a 409 Conflict response with the state the client conflicts with
+func RespondConflict(c *gin.Context, message string, code string, data interface{}) {
+	c.JSON(409, ErrorResponse{
+		Success: false,
+		Error:   message,
+		Code:    code,
+		Data:    data,
+	})
+}
+
 // RespondNotFound sends a 404 Not Found response
 func RespondNotFound(c *gin.Context, message string) {
 	RespondError(c, 404, message, "NOT_FOUND")
@@ -93,3 +115,13 @@ func RespondPaginated(c *gin.Context, data interface{}, pagination Pagination) {
 		Meta:    pagination,
 	})
 }
+
+// toPagination converts service pagination to handler pagination
+func toPagination(p *services.Pagination) Pagination {
+	return Pagination{
+		Page:       p.Page,
+		PerPage:    p.PerPage,
+		Total:      p.Total,
+		TotalPages: p.TotalPages,
+	}
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"hh_puzzle/internal/middleware"
+	"hh_puzzle/internal/services"
+)
+
+// SubscriptionHandler handles subscription HTTP requests
+type SubscriptionHandler struct {
+	subscriptionService services.SubscriptionService
+}
+
+// NewSubscriptionHandler creates a new subscription handler
+func NewSubscriptionHandler(subscriptionService services.SubscriptionService) *SubscriptionHandler {
+	return &SubscriptionHandler{
+		subscriptionService: subscriptionService,
+	}
+}
+
+// GetSubscriptions returns the user's subscriptions and when their access ends
+func (h *SubscriptionHandler) GetSubscriptions(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
+	subscriptions, err := h.subscriptionService.GetUserSubscriptions(claims.UserID)
+	if err != nil {
+		RespondInternalError(c, err.Error())
+		return
+	}
+
+	RespondSuccess(c, subscriptions, "")
+}
+
+// CancelSubscription stops a subscription from renewing at the end of its period
+func (h *SubscriptionHandler) CancelSubscription(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		RespondBadRequest(c, "Invalid subscription ID")
+		return
+	}
+
+	subscription, err := h.subscriptionService.Cancel(claims.UserID, uint(id))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrSubscriptionNotFound):
+			RespondNotFound(c, "Subscription not found")
+		case errors.Is(err, services.ErrSubscriptionExpired):
+			RespondError(c, 409, "Subscription has already expired", "SUBSCRIPTION_EXPIRED")
+		case errors.Is(err, services.ErrSubscriptionConflict):
+			RespondError(c, 409, "Subscription changed, reload and try again", "SUBSCRIPTION_CONFLICT")
+		default:
+			RespondInternalError(c, err.Error())
+		}
+		return
+	}
+
+	RespondSuccess(c, subscription, "Subscription will end at the close of the current period")
+}
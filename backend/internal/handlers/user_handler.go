@@ -1,6 +1,9 @@
 package handlers
 
 import (
+	"errors"
+	"strconv"
+
 	"github.com/gin-gonic/gin"
 	"hh_puzzle/internal/middleware"
 	"hh_puzzle/internal/services"
@@ -8,13 +11,15 @@ import (
 
 // UserHandler handles user-related HTTP requests
 type UserHandler struct {
-	userService services.UserService
+	userService   services.UserService
+	streakService services.StreakService
 }
 
 // NewUserHandler creates a new user handler
-func NewUserHandler(userService services.UserService) *UserHandler {
+func NewUserHandler(userService services.UserService, streakService services.StreakService) *UserHandler {
 	return &UserHandler{
-		userService: userService,
+		userService:   userService,
+		streakService: streakService,
 	}
 }
 
@@ -30,6 +35,7 @@ type UpdatePreferencesRequest struct {
 	MusicVolume  int    `json:"music_volume"`
 	Theme        string `json:"theme"`
 	Difficulty   string `json:"difficulty"`
+	Timezone     string `json:"timezone"` // IANA name, e.g. America/Los_Angeles
 }
 
 // GetProfile returns the user's profile
@@ -91,6 +97,7 @@ func (h *UserHandler) UpdatePreferences(c *gin.Context) {
 		req.MusicVolume,
 		req.Theme,
 		req.Difficulty,
+		req.Timezone,
 	); err != nil {
 		RespondBadRequest(c, err.Error())
 		return
@@ -131,3 +138,46 @@ func (h *UserHandler) DeleteAccount(c *gin.Context) {
 
 	RespondSuccess(c, nil, "Account deleted successfully")
 }
+
+// GetStreak returns the user's streak calendar and remaining freezes
+func (h *UserHandler) GetStreak(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+
+	calendar, err := h.streakService.GetStreak(claims.UserID, days)
+	if err != nil {
+		RespondNotFound(c, "Profile not found")
+		return
+	}
+
+	RespondSuccess(c, calendar, "")
+}
+
+// BuyStreakFreeze spends points on a streak freeze
+func (h *UserHandler) BuyStreakFreeze(c *gin.Context) {
+	claims, ok := middleware.GetUserFromContext(c)
+	if !ok {
+		RespondUnauthorized(c, "User not found in context")
+		return
+	}
+
+	calendar, err := h.streakService.BuyFreeze(claims.UserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrFreezeLimitReached):
+			RespondError(c, 409, "You already hold the maximum number of streak freezes", "FREEZE_LIMIT_REACHED")
+		case errors.Is(err, services.ErrNotEnoughPoints):
+			RespondError(c, 402, "Not enough points to buy a streak freeze", "NOT_ENOUGH_POINTS")
+		default:
+			RespondBadRequest(c, err.Error())
+		}
+		return
+	}
+
+	RespondSuccess(c, calendar, "Streak freeze purchased successfully")
+}
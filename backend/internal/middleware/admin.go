@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminMiddleware only lets configured admin accounts through.
+// It must run after AuthMiddleware.
+func AdminMiddleware(adminEmails []string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(adminEmails))
+	for _, email := range adminEmails {
+		allowed[strings.ToLower(email)] = true
+	}
+
+	return func(c *gin.Context) {
+		claims, ok := GetUserFromContext(c)
+		if !ok || claims.IsGuest || !allowed[strings.ToLower(claims.Email)] {
+			c.JSON(403, gin.H{
+				"success": false,
+				"error":   "Admin access required",
+				"code":    "FORBIDDEN",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -56,6 +56,21 @@ func AuthMiddleware() gin.HandlerFunc {
 	}
 }
 
+// WebSocketAuthMiddleware validates the same JWT tokens as AuthMiddleware on
+// WebSocket handshakes. Browsers cannot set headers when opening a WebSocket,
+// so the token may also be sent in the access_token query parameter.
+func WebSocketAuthMiddleware() gin.HandlerFunc {
+	authenticate := AuthMiddleware()
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			if token := c.Query("access_token"); token != "" {
+				c.Request.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		authenticate(c)
+	}
+}
+
 // GetUserFromContext extracts user claims from context
 func GetUserFromContext(c *gin.Context) (*utils.Claims, bool) {
 	claims, exists := c.Get(UserContextKey)
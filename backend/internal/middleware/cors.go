@@ -9,8 +9,9 @@ import (
 func CORSMiddleware() gin.HandlerFunc {
 	config := cors.Config{
 		AllowOrigins:     []string{"*"}, // TODO: Restrict in production
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Content-Type", "Authorization"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Content-Type", "Authorization", "Idempotency-Key", "If-Match", "If-None-Match"},
+		ExposeHeaders:    []string{"ETag", "Idempotent-Replayed"},
 		AllowCredentials: true,
 		MaxAge:           3600,
 	}
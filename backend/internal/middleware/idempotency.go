@@ -0,0 +1,215 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"hh_puzzle/internal/models"
+	"hh_puzzle/internal/repository"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header clients use to make a
+	// mutating request safe to retry
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// IdempotencyReplayedHeader is set on responses served from storage
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+
+	// IdempotencyKeyTTL is how long a stored response can be replayed
+	IdempotencyKeyTTL = 24 * time.Hour
+
+	maxIdempotencyKeyLength = 255
+
+	// Anonymous keys are only told apart by the key itself, so they must be
+	// long enough not to be guessed
+	minAnonymousIdempotencyKeyLength = 16
+)
+
+// IdempotencyMiddleware replays the stored response when a request is retried
+// with the same Idempotency-Key. Keys are scoped to the user, method and request
+// path; reusing one with a different body is rejected. Anonymous requests are
+// scoped to user 0, the key and the request body, so their keys must be hard
+// to guess. Requests without the header pass through untouched. On
+// authenticated routes it must run after AuthMiddleware.
+func IdempotencyMiddleware(store repository.IdempotencyRepository, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		var userID uint
+		if claims, ok := GetUserFromContext(c); ok {
+			userID = claims.UserID
+		}
+		if userID == 0 && len(key) < minAnonymousIdempotencyKeyLength {
+			c.JSON(400, gin.H{
+				"success": false,
+				"error":   "Idempotency key is too short",
+				"code":    "INVALID_IDEMPOTENCY_KEY",
+			})
+			c.Abort()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(400, gin.H{
+				"success": false,
+				"error":   "Idempotency key is too long",
+				"code":    "INVALID_IDEMPOTENCY_KEY",
+			})
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(400, gin.H{
+				"success": false,
+				"error":   "Failed to read request body",
+				"code":    "INVALID_REQUEST",
+			})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.Sum256(body)
+		now := time.Now()
+		record := &models.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			Route:       c.Request.Method + " " + c.Request.URL.Path,
+			RequestHash: hex.EncodeToString(hash[:]),
+			ExpiresAt:   now.Add(ttl),
+		}
+
+		reserved, err := reserveIdempotencyKey(store, record, now)
+		if err != nil {
+			log.Printf("Idempotency key lookup failed: %v", err)
+			c.JSON(500, gin.H{
+				"success": false,
+				"error":   "Internal server error",
+				"code":    "INTERNAL_ERROR",
+			})
+			c.Abort()
+			return
+		}
+		if !reserved {
+			replayIdempotentResponse(c, store, record)
+			return
+		}
+
+		// Release the key unless a response gets stored, so a failed or
+		// panicking request can be retried with the same key
+		stored := false
+		defer func() {
+			if !stored {
+				if err := store.Delete(record); err != nil {
+					log.Printf("Failed to release idempotency key: %v", err)
+				}
+			}
+		}()
+
+		writer := &bodyCaptureWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		// Server errors are not stored; the retry should run the request again
+		status := c.Writer.Status()
+		if status >= 500 {
+			return
+		}
+
+		completedAt := time.Now()
+		record.StatusCode = status
+		record.ContentType = c.Writer.Header().Get("Content-Type")
+		record.ResponseBody = writer.body.Bytes()
+		record.CompletedAt = &completedAt
+		if err := store.Complete(record); err != nil {
+			log.Printf("Failed to store idempotent response: %v", err)
+			return
+		}
+		stored = true
+	}
+}
+
+// reserveIdempotencyKey claims the key for this request. An expired record
+// left behind for the key is removed and the claim retried once.
+func reserveIdempotencyKey(store repository.IdempotencyRepository, record *models.IdempotencyKey, now time.Time) (bool, error) {
+	reserved, err := store.Reserve(record)
+	if err != nil || reserved {
+		return reserved, err
+	}
+
+	existing, err := store.Find(record.UserID, record.Key, record.Route)
+	if err != nil {
+		// Released between the insert and the lookup
+		return store.Reserve(record)
+	}
+	if existing.ExpiresAt.After(now) {
+		return false, nil
+	}
+	if err := store.Delete(existing); err != nil {
+		return false, err
+	}
+	return store.Reserve(record)
+}
+
+// replayIdempotentResponse answers a retried request from the stored record
+func replayIdempotentResponse(c *gin.Context, store repository.IdempotencyRepository, record *models.IdempotencyKey) {
+	defer c.Abort()
+
+	existing, err := store.Find(record.UserID, record.Key, record.Route)
+	if err != nil {
+		c.JSON(409, gin.H{
+			"success": false,
+			"error":   "A request with this idempotency key is already in progress",
+			"code":    "IDEMPOTENCY_KEY_IN_PROGRESS",
+		})
+		return
+	}
+
+	if existing.RequestHash != record.RequestHash {
+		c.JSON(422, gin.H{
+			"success": false,
+			"error":   "Idempotency key was already used with a different request body",
+			"code":    "IDEMPOTENCY_KEY_REUSED",
+		})
+		return
+	}
+
+	if !existing.IsCompleted() {
+		c.JSON(409, gin.H{
+			"success": false,
+			"error":   "A request with this idempotency key is already in progress",
+			"code":    "IDEMPOTENCY_KEY_IN_PROGRESS",
+		})
+		return
+	}
+
+	c.Header(IdempotencyReplayedHeader, "true")
+	c.Data(existing.StatusCode, existing.ContentType, existing.ResponseBody)
+}
+
+// bodyCaptureWriter keeps a copy of everything written to the response
+type bodyCaptureWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyCaptureWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bodyCaptureWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Achievement rule types
+const (
+	RulePuzzlesCompleted = "puzzles_completed" // complete Count puzzles matching the filters
+	RuleNoHintSolve      = "no_hint_solve"     // solve a puzzle matching the filters without hints
+	RuleCollection       = "collection"        // complete every puzzle matching the filters
+	RuleStreak           = "streak"            // reach a streak of Days
+	RuleTotalPoints      = "total_points"      // reach Points in total
+)
+
+// AchievementCriteria holds the parameters of an achievement rule, stored as
+// JSONB. Which fields apply depends on the rule type; empty filters match any puzzle.
+type AchievementCriteria struct {
+	Count      int    `json:"count,omitempty"`
+	Days       int    `json:"days,omitempty"`
+	Points     int    `json:"points,omitempty"`
+	Difficulty string `json:"difficulty,omitempty"`
+	Decade     string `json:"decade,omitempty"`
+	Region     string `json:"region,omitempty"`
+	Subgenre   string `json:"subgenre,omitempty"`
+}
+
+// Achievement is a badge definition. Rules live in the database so new badges
+// can be added without a deploy.
+type Achievement struct {
+	ID          uint                `gorm:"primaryKey" json:"id"`
+	Code        string              `gorm:"size:50;uniqueIndex;not null" json:"code"`
+	Name        string              `gorm:"size:100;not null" json:"name"`
+	Description string              `gorm:"type:text" json:"description"`
+	IconURL     string              `gorm:"size:500" json:"icon_url,omitempty"`
+	RuleType    string              `gorm:"size:50;not null" json:"rule_type"`
+	Criteria    AchievementCriteria `gorm:"type:jsonb" json:"criteria"`
+	IsActive    bool                `gorm:"default:true;index" json:"is_active"`
+	CreatedAt   time.Time           `json:"created_at"`
+}
+
+// TableName specifies the table name for Achievement model
+func (Achievement) TableName() string {
+	return "achievements"
+}
+
+// UserAchievement records an achievement awarded to a user
+type UserAchievement struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	UserID        uint      `gorm:"not null;uniqueIndex:idx_user_achievement" json:"user_id"`
+	AchievementID uint      `gorm:"not null;uniqueIndex:idx_user_achievement" json:"achievement_id"`
+	PuzzleID      *uint     `json:"puzzle_id,omitempty"` // puzzle whose completion earned it
+	AwardedAt     time.Time `json:"awarded_at"`
+
+	// Relationships
+	User        User        `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Achievement Achievement `gorm:"foreignKey:AchievementID;constraint:OnDelete:CASCADE" json:"achievement,omitempty"`
+}
+
+// TableName specifies the table name for UserAchievement model
+func (UserAchievement) TableName() string {
+	return "user_achievements"
+}
+
+// Value implements the driver.Valuer interface
+func (c AchievementCriteria) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// Scan implements the sql.Scanner interface
+func (c *AchievementCriteria) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, c)
+}
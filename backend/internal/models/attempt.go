@@ -2,21 +2,34 @@ package models
 
 import "time"
 
-// PuzzleAttempt represents a user's attempt at solving a puzzle
+// PuzzleAttempt represents one run of a user at solving a puzzle. Run 1 is
+// the scored run; later runs are practice replays of a solved puzzle.
 type PuzzleAttempt struct {
 	ID                 uint      `gorm:"primaryKey" json:"id"`
-	UserID             uint      `gorm:"not null;index;uniqueIndex:idx_user_puzzle" json:"user_id"`
-	PuzzleID           uint      `gorm:"not null;index;uniqueIndex:idx_user_puzzle" json:"puzzle_id"`
+	UserID             uint      `gorm:"not null;index;uniqueIndex:idx_user_puzzle_run" json:"user_id"`
+	PuzzleID           uint      `gorm:"not null;index;uniqueIndex:idx_user_puzzle_run" json:"puzzle_id"`
+	RunNumber          int       `gorm:"not null;default:1;uniqueIndex:idx_user_puzzle_run" json:"run_number"`
 	
 	// Progress tracking
 	CurrentState       JSONB     `gorm:"type:jsonb" json:"current_state,omitempty"`
+	Version            int       `gorm:"not null;default:0" json:"version"` // increases with every change to CurrentState
+	CellVersions       JSONB     `gorm:"type:jsonb" json:"-"`               // "x,y" -> version that last changed the cell
+	CellAuthors        JSONB     `gorm:"type:jsonb" json:"-"`               // "x,y" -> member who filled the cell, for shared attempts
 	IsCompleted        bool      `gorm:"default:false;index" json:"is_completed"`
-	CompletionTime     *int      `json:"completion_time,omitempty"` // in seconds
+	CompletionTime     *Seconds  `gorm:"column:completion_seconds" json:"completion_seconds,omitempty"` // active time measured by the server
+	ClientCompletionTime *Seconds `gorm:"column:client_completion_seconds" json:"client_completion_seconds,omitempty"` // time reported by the app
+	TimeMismatch       bool      `gorm:"default:false" json:"time_mismatch"` // client time disagreed with the server's
 	
+	// Co-op play; members of a shared attempt join it with the share code
+	IsShared           bool      `gorm:"default:false" json:"is_shared"`
+	ShareCode          *string   `gorm:"size:16;uniqueIndex" json:"share_code,omitempty"`
+
 	// Scoring
 	HintsUsed          int       `gorm:"default:0" json:"hints_used"`
 	PointsEarned       int       `gorm:"default:0;index" json:"points_earned"`
 	AccuracyPercentage *float64  `gorm:"type:decimal(5,2)" json:"accuracy_percentage,omitempty"`
+	ScoringPolicyVersion string          `gorm:"size:50" json:"scoring_policy_version,omitempty"`
+	ScoreBreakdown       *ScoreBreakdown `gorm:"type:jsonb" json:"score_breakdown,omitempty"`
 	
 	// Timestamps
 	CreatedAt          time.Time  `json:"created_at"`
@@ -25,11 +38,20 @@ type PuzzleAttempt struct {
 	UpdatedAt          time.Time  `json:"updated_at"`
 
 	// Relationships
-	User   User   `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
-	Puzzle Puzzle `gorm:"foreignKey:PuzzleID;constraint:OnDelete:CASCADE" json:"puzzle,omitempty"`
+	User   User          `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Puzzle Puzzle        `gorm:"foreignKey:PuzzleID;constraint:OnDelete:CASCADE" json:"puzzle,omitempty"`
+	Hints  []AttemptHint `gorm:"foreignKey:AttemptID;constraint:OnDelete:CASCADE" json:"hints,omitempty"`
+	Pauses []AttemptPause `gorm:"foreignKey:AttemptID;constraint:OnDelete:CASCADE" json:"pauses,omitempty"`
+	Members []AttemptMember `gorm:"foreignKey:AttemptID;constraint:OnDelete:CASCADE" json:"members,omitempty"`
 }
 
 // TableName specifies the table name for PuzzleAttempt model
 func (PuzzleAttempt) TableName() string {
 	return "puzzle_attempts"
-}
\ No newline at end of file
+}
+
+// IsReplay reports whether the attempt is a practice run of a puzzle the user
+// already completed. Replays do not count toward points or leaderboards.
+func (a *PuzzleAttempt) IsReplay() bool {
+	return a.RunNumber > 1
+}
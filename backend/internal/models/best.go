@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// PuzzleBest tracks a user's best time on a puzzle across all runs. Only fully
+// solved runs can set the best time.
+type PuzzleBest struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	UserID        uint      `gorm:"not null;uniqueIndex:idx_puzzle_bests_user_puzzle" json:"user_id"`
+	PuzzleID      uint      `gorm:"not null;uniqueIndex:idx_puzzle_bests_user_puzzle" json:"puzzle_id"`
+	BestTime      *Seconds  `gorm:"column:best_time_seconds" json:"best_time_seconds,omitempty"`
+	BestAttemptID *uint     `json:"best_attempt_id,omitempty"`
+	RunsCompleted int       `gorm:"default:0" json:"runs_completed"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	// Relationships
+	User   User   `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Puzzle Puzzle `gorm:"foreignKey:PuzzleID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name for PuzzleBest model
+func (PuzzleBest) TableName() string {
+	return "puzzle_bests"
+}
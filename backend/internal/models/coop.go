@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Shared attempt member roles
+const (
+	MemberRoleHost  = "host"  // the attempt's owner
+	MemberRoleGuest = "guest" // joined with the share code
+)
+
+// MaxAttemptMembers is how many players, host included, can share an attempt
+const MaxAttemptMembers = 4
+
+// AttemptMember is a player solving a shared attempt. When the attempt is
+// submitted its points are split between the members by the correct cells
+// each of them filled, and each member's share is recorded here.
+type AttemptMember struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	AttemptID    uint      `gorm:"not null;index;uniqueIndex:idx_attempt_member" json:"attempt_id"`
+	UserID       uint      `gorm:"not null;index;uniqueIndex:idx_attempt_member" json:"user_id"`
+	Role         string    `gorm:"size:20;not null" json:"role"`
+	CorrectCells int       `gorm:"default:0" json:"correct_cells"` // correct cells the member filled, set on submit
+	PointsEarned int       `gorm:"default:0" json:"points_earned"` // the member's share, set on submit
+	JoinedAt     time.Time `gorm:"not null" json:"joined_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	// Relationships
+	User    User          `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Attempt PuzzleAttempt `gorm:"foreignKey:AttemptID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name for AttemptMember model
+func (AttemptMember) TableName() string {
+	return "attempt_members"
+}
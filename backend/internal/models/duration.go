@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Seconds is a duration stored and sent over the API as whole seconds. Every
+// duration column and field uses it so minutes and seconds cannot be mixed up.
+type Seconds int
+
+// SecondsOf converts a duration to whole seconds, rounding down
+func SecondsOf(d time.Duration) Seconds {
+	return Seconds(d / time.Second)
+}
+
+// Minutes returns a duration of whole minutes in seconds
+func Minutes(minutes int) Seconds {
+	return Seconds(minutes * 60)
+}
+
+// Duration converts the seconds to a time.Duration
+func (s Seconds) Duration() time.Duration {
+	return time.Duration(s) * time.Second
+}
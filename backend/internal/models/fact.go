@@ -2,6 +2,13 @@ package models
 
 import "time"
 
+// Fact unlock types
+const (
+	UnlockPuzzleCompletion = "puzzle_completion"
+	UnlockPointsMilestone  = "points_milestone"
+	UnlockStreak           = "streak"
+)
+
 // HipHopFact represents educational hip-hop content
 type HipHopFact struct {
 	ID          uint      `gorm:"primaryKey" json:"id"`
@@ -0,0 +1,240 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Clue directions
+const (
+	DirectionAcross = "across"
+	DirectionDown   = "down"
+)
+
+// PuzzleGrid is the typed grid of a crossword, stored as JSONB.
+// All matrices are indexed [y][x] and sized Height x Width.
+type PuzzleGrid struct {
+	Width    int        `json:"width"`
+	Height   int        `json:"height"`
+	Cells    [][]string `json:"cells"`    // solution letter, empty for blocks
+	Blocks   [][]bool   `json:"blocks"`   // true for black squares
+	Numbers  [][]int    `json:"numbers"`  // clue number, 0 when unnumbered
+	Revealed [][]bool   `json:"revealed"` // letters shown to the player at the start
+}
+
+// Clue is a single crossword clue with its answer, stored as part of a ClueList
+type Clue struct {
+	Number    int    `json:"number"`
+	Direction string `json:"direction"` // across, down
+	Text      string `json:"text"`
+	Length    int    `json:"length"`
+	X         int    `json:"x"`
+	Y         int    `json:"y"`
+	Answer    string `json:"answer"`
+}
+
+// ClueList is a list of clues stored as a JSONB array
+type ClueList []Clue
+
+// NewPuzzleGrid creates an empty grid where every cell is a block
+func NewPuzzleGrid(width, height int) PuzzleGrid {
+	grid := PuzzleGrid{
+		Width:    width,
+		Height:   height,
+		Cells:    make([][]string, height),
+		Blocks:   make([][]bool, height),
+		Numbers:  make([][]int, height),
+		Revealed: make([][]bool, height),
+	}
+	for y := 0; y < height; y++ {
+		grid.Cells[y] = make([]string, width)
+		grid.Blocks[y] = make([]bool, width)
+		grid.Numbers[y] = make([]int, width)
+		grid.Revealed[y] = make([]bool, width)
+		for x := 0; x < width; x++ {
+			grid.Blocks[y][x] = true
+		}
+	}
+	return grid
+}
+
+// BuildPuzzleGrid lays out the clues on a grid just large enough to hold them
+func BuildPuzzleGrid(across, down ClueList) PuzzleGrid {
+	width, height := 0, 0
+	for _, clue := range append(append(ClueList{}, across...), down...) {
+		endX, endY := clue.Cell(clue.Length - 1)
+		if endX+1 > width {
+			width = endX + 1
+		}
+		if endY+1 > height {
+			height = endY + 1
+		}
+	}
+
+	grid := NewPuzzleGrid(width, height)
+	for _, clue := range across {
+		grid.Place(clue)
+	}
+	for _, clue := range down {
+		grid.Place(clue)
+	}
+	return grid
+}
+
+// InBounds reports whether a cell lies inside the grid
+func (g PuzzleGrid) InBounds(x, y int) bool {
+	return x >= 0 && y >= 0 && x < g.Width && y < g.Height
+}
+
+// Place writes a clue's answer and number into the grid
+func (g *PuzzleGrid) Place(clue Clue) {
+	for i := 0; i < len(clue.Answer); i++ {
+		x, y := clue.Cell(i)
+		if !g.InBounds(x, y) {
+			continue
+		}
+		g.Cells[y][x] = string(clue.Answer[i])
+		g.Blocks[y][x] = false
+	}
+	if g.InBounds(clue.X, clue.Y) {
+		g.Numbers[clue.Y][clue.X] = clue.Number
+	}
+}
+
+// Reveal marks a letter cell as shown to the player at the start
+func (g *PuzzleGrid) Reveal(x, y int) {
+	if g.InBounds(x, y) && !g.Blocks[y][x] {
+		g.Revealed[y][x] = true
+	}
+}
+
+// Validate checks that the grid matrices match its dimensions and each other
+func (g PuzzleGrid) Validate() error {
+	if g.Width <= 0 || g.Height <= 0 {
+		return errors.New("grid dimensions must be positive")
+	}
+	if len(g.Cells) != g.Height || len(g.Blocks) != g.Height ||
+		len(g.Numbers) != g.Height || len(g.Revealed) != g.Height {
+		return errors.New("grid rows do not match height")
+	}
+
+	for y := 0; y < g.Height; y++ {
+		if len(g.Cells[y]) != g.Width || len(g.Blocks[y]) != g.Width ||
+			len(g.Numbers[y]) != g.Width || len(g.Revealed[y]) != g.Width {
+			return fmt.Errorf("grid row %d does not match width", y)
+		}
+		for x := 0; x < g.Width; x++ {
+			if g.Blocks[y][x] != (g.Cells[y][x] == "") {
+				return fmt.Errorf("cell %d,%d is inconsistent with the block mask", x, y)
+			}
+			if g.Blocks[y][x] && (g.Numbers[y][x] != 0 || g.Revealed[y][x]) {
+				return fmt.Errorf("block %d,%d cannot be numbered or revealed", x, y)
+			}
+		}
+	}
+	return nil
+}
+
+// Cell returns the grid position of the i-th letter of the answer
+func (c Clue) Cell(i int) (int, int) {
+	if c.Direction == DirectionDown {
+		return c.X, c.Y + i
+	}
+	return c.X + i, c.Y
+}
+
+// Validate checks a clue against the grid it belongs to
+func (c Clue) Validate(grid PuzzleGrid, direction string) error {
+	if c.Direction != direction {
+		return fmt.Errorf("clue %d is listed as %s but has direction %q", c.Number, direction, c.Direction)
+	}
+	if c.Number <= 0 {
+		return errors.New("clue number must be positive")
+	}
+	if c.Length != len(c.Answer) || c.Length == 0 {
+		return fmt.Errorf("clue %d %s length does not match its answer", c.Number, direction)
+	}
+
+	for i := 0; i < c.Length; i++ {
+		x, y := c.Cell(i)
+		if !grid.InBounds(x, y) {
+			return fmt.Errorf("clue %d %s runs outside the grid", c.Number, direction)
+		}
+		if grid.Cells[y][x] != string(c.Answer[i]) {
+			return fmt.Errorf("clue %d %s does not match the grid at %d,%d", c.Number, direction, x, y)
+		}
+	}
+
+	if grid.Numbers[c.Y][c.X] != c.Number {
+		return fmt.Errorf("clue %d %s does not match the grid numbering", c.Number, direction)
+	}
+	return nil
+}
+
+// Clues returns all clues of the puzzle, across clues first
+func (p *Puzzle) Clues() ClueList {
+	clues := make(ClueList, 0, len(p.CluesAcross)+len(p.CluesDown))
+	clues = append(clues, p.CluesAcross...)
+	return append(clues, p.CluesDown...)
+}
+
+// FindClue returns the clue with the given number and direction
+func (p *Puzzle) FindClue(number int, direction string) (*Clue, bool) {
+	for _, clue := range p.Clues() {
+		if clue.Number == number && clue.Direction == direction {
+			return &clue, true
+		}
+	}
+	return nil, false
+}
+
+// ValidateGrid checks that the grid and clues of a puzzle are consistent
+func (p *Puzzle) ValidateGrid() error {
+	if err := p.GridData.Validate(); err != nil {
+		return fmt.Errorf("invalid puzzle grid: %w", err)
+	}
+	for _, clue := range p.CluesAcross {
+		if err := clue.Validate(p.GridData, DirectionAcross); err != nil {
+			return fmt.Errorf("invalid puzzle clue: %w", err)
+		}
+	}
+	for _, clue := range p.CluesDown {
+		if err := clue.Validate(p.GridData, DirectionDown); err != nil {
+			return fmt.Errorf("invalid puzzle clue: %w", err)
+		}
+	}
+	return nil
+}
+
+// Value implements the driver.Valuer interface
+func (g PuzzleGrid) Value() (driver.Value, error) {
+	return json.Marshal(g)
+}
+
+// Scan implements the sql.Scanner interface
+func (g *PuzzleGrid) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, g)
+}
+
+// Value implements the driver.Valuer interface
+func (l ClueList) Value() (driver.Value, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(l)
+}
+
+// Scan implements the sql.Scanner interface
+func (l *ClueList) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, (*[]Clue)(l))
+}
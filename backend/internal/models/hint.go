@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Hint types a player can use during an attempt
+const (
+	HintRevealLetter = "reveal_letter"
+	HintRevealWord   = "reveal_word"
+	HintCheckWord    = "check_word"
+)
+
+// AttemptHint records a single hint used during a puzzle attempt
+type AttemptHint struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	AttemptID  uint      `gorm:"not null;index" json:"attempt_id"`
+	HintType   string    `gorm:"size:20;not null" json:"hint_type"` // reveal_letter, reveal_word, check_word
+	ClueNumber int       `gorm:"not null" json:"clue_number"`
+	Direction  string    `gorm:"size:10;not null" json:"direction"` // across, down
+	Cells      JSONB     `gorm:"type:jsonb" json:"cells,omitempty"` // revealed letters or checked results keyed by "x,y"
+	CreatedAt  time.Time `json:"created_at"`
+
+	// Relationships
+	Attempt PuzzleAttempt `gorm:"foreignKey:AttemptID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name for AttemptHint model
+func (AttemptHint) TableName() string {
+	return "attempt_hints"
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// IdempotencyKey stores the response of a mutating request made with an
+// Idempotency-Key header so that client retries get the same response back.
+// UserID is 0 for anonymous requests such as guest sign-up.
+type IdempotencyKey struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	UserID       uint       `gorm:"not null;uniqueIndex:idx_idempotency_keys_scope" json:"user_id"`
+	Key          string     `gorm:"size:255;not null;uniqueIndex:idx_idempotency_keys_scope" json:"key"`
+	Route        string     `gorm:"size:255;not null;uniqueIndex:idx_idempotency_keys_scope" json:"route"` // method and request path
+	RequestHash  string     `gorm:"size:64;not null" json:"request_hash"`                                  // SHA-256 of the request body
+	StatusCode   int        `json:"status_code"`                                                           // 0 while the request is in flight
+	ContentType  string     `gorm:"size:100" json:"content_type"`
+	ResponseBody []byte     `json:"-"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt    time.Time  `gorm:"not null;index" json:"expires_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// TableName specifies the table name for IdempotencyKey model
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
+
+// IsCompleted reports whether the response has been stored
+func (k *IdempotencyKey) IsCompleted() bool {
+	return k.CompletedAt != nil
+}
@@ -14,7 +14,7 @@ type Leaderboard struct {
 	// Stats for the week
 	TotalPoints           int       `gorm:"default:0" json:"total_points"`
 	PuzzlesCompleted      int       `gorm:"default:0" json:"puzzles_completed"`
-	AverageCompletionTime *int      `json:"average_completion_time,omitempty"` // in seconds
+	AverageCompletionTime *Seconds  `gorm:"column:average_completion_seconds" json:"average_completion_seconds,omitempty"`
 	
 	// Ranking
 	Rank                  *int      `gorm:"index" json:"rank,omitempty"`
@@ -2,6 +2,13 @@ package models
 
 import "time"
 
+// Music moods
+const (
+	MoodChill     = "chill"
+	MoodEnergetic = "energetic"
+	MoodFocused   = "focused"
+)
+
 // MusicTrack represents background music for the game
 type MusicTrack struct {
 	ID              uint      `gorm:"primaryKey" json:"id"`
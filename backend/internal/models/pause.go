@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// AttemptPause records an interval in which an attempt's timer was stopped,
+// such as while the app was in the background. ResumedAt is nil while the
+// attempt is paused; an attempt has at most one open pause.
+type AttemptPause struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	AttemptID uint       `gorm:"not null;index;uniqueIndex:idx_attempt_pauses_open,where:resumed_at IS NULL" json:"attempt_id"`
+	PausedAt  time.Time  `gorm:"not null" json:"paused_at"`
+	ResumedAt *time.Time `json:"resumed_at,omitempty"`
+
+	// Relationships
+	Attempt PuzzleAttempt `gorm:"foreignKey:AttemptID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name for AttemptPause model
+func (AttemptPause) TableName() string {
+	return "attempt_pauses"
+}
@@ -2,6 +2,21 @@ package models
 
 import "time"
 
+// Purchase statuses
+const (
+	PurchaseStatusPending   = "pending"
+	PurchaseStatusCompleted = "completed"
+	PurchaseStatusFailed    = "failed"
+	PurchaseStatusRefunded  = "refunded"
+)
+
+// Subscription statuses
+const (
+	SubscriptionStatusActive    = "active"
+	SubscriptionStatusCancelled = "cancelled"
+	SubscriptionStatusExpired   = "expired"
+)
+
 // Purchase represents a puzzle pack purchase or subscription
 type Purchase struct {
 	ID                    uint       `gorm:"primaryKey" json:"id"`
@@ -11,7 +26,7 @@ type Purchase struct {
 	// Payment details
 	AmountUSD             float64    `gorm:"type:decimal(10,2);not null" json:"amount_usd"`
 	Currency              string     `gorm:"size:3;default:'USD'" json:"currency"`
-	PaymentProvider       string     `gorm:"size:50" json:"payment_provider,omitempty"` // stripe, apple, google
+	PaymentProvider       string     `gorm:"size:50" json:"payment_provider,omitempty"` // stripe, apple, google, local
 	TransactionID         string     `gorm:"size:255;uniqueIndex" json:"transaction_id,omitempty"`
 	
 	// Subscription details
@@ -13,9 +13,9 @@ type Puzzle struct {
 	Description         string         `gorm:"type:text" json:"description"`
 	
 	// Grid data stored as JSONB
-	GridData            JSONB          `gorm:"type:jsonb;not null" json:"grid_data"`
-	CluesAcross         JSONB          `gorm:"type:jsonb;not null" json:"clues_across"`
-	CluesDown           JSONB          `gorm:"type:jsonb;not null" json:"clues_down"`
+	GridData            PuzzleGrid     `gorm:"type:jsonb;not null" json:"grid_data"`
+	CluesAcross         ClueList       `gorm:"type:jsonb;not null" json:"clues_across"`
+	CluesDown           ClueList       `gorm:"type:jsonb;not null" json:"clues_down"`
 	
 	// Categorization
 	Difficulty          string         `gorm:"size:20;not null;index" json:"difficulty"` // beginner, intermediate, expert
@@ -24,10 +24,12 @@ type Puzzle struct {
 	Subgenre            string         `gorm:"size:50" json:"subgenre,omitempty"` // Trap, Boom Bap, etc.
 	
 	// Metadata
-	EstimatedTime       int            `json:"estimated_time,omitempty"` // in minutes
+	ParTime             Seconds        `gorm:"column:par_time_seconds" json:"par_time_seconds,omitempty"` // expected solve time
+	ParTimeSamples      int            `gorm:"default:0" json:"par_time_samples"`                         // completions the par time is derived from, 0 for the difficulty default
 	BasePoints          int            `gorm:"default:100" json:"base_points"`
 	IsDailyChallenge    bool           `gorm:"default:false;index" json:"is_daily_challenge"`
 	DailyChallengeDate  *time.Time     `gorm:"uniqueIndex" json:"daily_challenge_date,omitempty"`
+	ScoringOverrides    ScoringOverrides `gorm:"type:jsonb" json:"scoring_overrides"`
 	
 	// Pack association
 	PuzzlePackID        *uint          `gorm:"index" json:"puzzle_pack_id,omitempty"`
@@ -46,6 +48,19 @@ func (Puzzle) TableName() string {
 	return "puzzles"
 }
 
+// BeforeSave hook to reject inconsistent grids and clues
+func (p *Puzzle) BeforeSave(tx *gorm.DB) error {
+	return p.ValidateGrid()
+}
+
+// AfterFind hook to rebuild grids of puzzles stored before the typed grid schema
+func (p *Puzzle) AfterFind(tx *gorm.DB) error {
+	if p.GridData.Width == 0 && len(p.Clues()) > 0 {
+		p.GridData = BuildPuzzleGrid(p.CluesAcross, p.CluesDown)
+	}
+	return nil
+}
+
 // PuzzlePack represents a collection of puzzles for purchase
 type PuzzlePack struct {
 	ID            uint      `gorm:"primaryKey" json:"id"`
@@ -65,6 +80,9 @@ type PuzzlePack struct {
 	CoverImageURL string    `gorm:"size:500" json:"cover_image_url,omitempty"`
 	IsActive      bool      `gorm:"default:true;index" json:"is_active"`
 	
+	// Scoring adjustments for every puzzle in the pack
+	ScoringOverrides ScoringOverrides `gorm:"type:jsonb" json:"scoring_overrides"`
+	
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
 
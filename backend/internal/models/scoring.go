@@ -0,0 +1,151 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// Score breakdown rules
+const (
+	ScoreRuleBase           = "base"            // base points scaled by accuracy
+	ScoreRuleTimeBonus      = "time_bonus"      // faster than the par time
+	ScoreRuleHintPenalty    = "hint_penalty"    // per hint used
+	ScoreRuleAccuracyBonus  = "accuracy_bonus"  // share of the accuracy percentage
+	ScoreRuleMultiplier     = "multiplier"      // flat multiplier from a puzzle or pack override
+	ScoreRuleDailyChallenge = "daily_challenge" // daily challenge multiplier
+	ScoreRuleStreak         = "streak"          // multiplier growing with the player's streak
+)
+
+// DifficultyDefaults are the points and par time new puzzles of a difficulty
+// start with, until enough completions exist to derive a par time
+type DifficultyDefaults struct {
+	BasePoints int
+	ParTime    Seconds
+}
+
+var difficultyDefaults = map[string]DifficultyDefaults{
+	"beginner":     {BasePoints: 100, ParTime: Minutes(15)},
+	"intermediate": {BasePoints: 200, ParTime: Minutes(20)},
+	"expert":       {BasePoints: 300, ParTime: Minutes(30)},
+}
+
+// DefaultsForDifficulty returns the defaults of a difficulty, falling back to beginner
+func DefaultsForDifficulty(difficulty string) DifficultyDefaults {
+	if defaults, ok := difficultyDefaults[difficulty]; ok {
+		return defaults
+	}
+	return difficultyDefaults["beginner"]
+}
+
+// ScoringOverrides adjusts the scoring policy for a puzzle or a whole pack,
+// stored as JSONB. Unset fields keep the policy's own setting.
+type ScoringOverrides struct {
+	HintPenalty              *int    `json:"hint_penalty,omitempty"`               // points lost per hint
+	MaxTimeBonus             *int    `json:"max_time_bonus,omitempty"`             // time bonus for an instant solve
+	Multiplier               float64 `json:"multiplier,omitempty"`                 // applied to every completion
+	DailyChallengeMultiplier float64 `json:"daily_challenge_multiplier,omitempty"` // applied when the puzzle is a daily challenge
+	StreakBonusPerDay        float64 `json:"streak_bonus_per_day,omitempty"`       // extra multiplier per streak day
+	MaxStreakBonus           float64 `json:"max_streak_bonus,omitempty"`           // cap on the streak multiplier's extra
+}
+
+// IsZero reports whether no override is set
+func (o ScoringOverrides) IsZero() bool {
+	return o == ScoringOverrides{}
+}
+
+// Merge returns the overrides with every field set in other taking precedence
+func (o ScoringOverrides) Merge(other ScoringOverrides) ScoringOverrides {
+	if other.HintPenalty != nil {
+		o.HintPenalty = other.HintPenalty
+	}
+	if other.MaxTimeBonus != nil {
+		o.MaxTimeBonus = other.MaxTimeBonus
+	}
+	if other.Multiplier != 0 {
+		o.Multiplier = other.Multiplier
+	}
+	if other.DailyChallengeMultiplier != 0 {
+		o.DailyChallengeMultiplier = other.DailyChallengeMultiplier
+	}
+	if other.StreakBonusPerDay != 0 {
+		o.StreakBonusPerDay = other.StreakBonusPerDay
+	}
+	if other.MaxStreakBonus != 0 {
+		o.MaxStreakBonus = other.MaxStreakBonus
+	}
+	return o
+}
+
+// Value implements the driver.Valuer interface
+func (o ScoringOverrides) Value() (driver.Value, error) {
+	return json.Marshal(o)
+}
+
+// Scan implements the sql.Scanner interface
+func (o *ScoringOverrides) Scan(value interface{}) error {
+	if value == nil {
+		*o = ScoringOverrides{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, o)
+}
+
+// ScoreItem is one line of a score breakdown
+type ScoreItem struct {
+	Rule   string `json:"rule"`
+	Points int    `json:"points"`
+}
+
+// ScoreMultiplier is a factor applied to the summed score items
+type ScoreMultiplier struct {
+	Rule   string  `json:"rule"`
+	Factor float64 `json:"factor"`
+}
+
+// ScoreBreakdown explains how an attempt's points were calculated. It keeps
+// the inputs and overrides used so the score can be recalculated later.
+type ScoreBreakdown struct {
+	PolicyVersion string            `json:"policy_version"`
+	Overrides     *ScoringOverrides `json:"overrides,omitempty"`
+
+	// Inputs
+	Accuracy       float64 `json:"accuracy"`
+	CompletionTime Seconds `json:"completion_seconds"`
+	ParTime        Seconds `json:"par_time_seconds"`
+	HintsUsed      int     `json:"hints_used"`
+	Streak         int     `json:"streak"`
+
+	Items       []ScoreItem       `json:"items"`
+	Multipliers []ScoreMultiplier `json:"multipliers,omitempty"`
+	Subtotal    int               `json:"subtotal"` // sum of items, never negative
+	Total       int               `json:"total"`
+}
+
+// Points returns the points of the item for a rule, or 0 when absent
+func (b *ScoreBreakdown) Points(rule string) int {
+	for _, item := range b.Items {
+		if item.Rule == rule {
+			return item.Points
+		}
+	}
+	return 0
+}
+
+// Value implements the driver.Valuer interface
+func (b ScoreBreakdown) Value() (driver.Value, error) {
+	return json.Marshal(b)
+}
+
+// Scan implements the sql.Scanner interface
+func (b *ScoreBreakdown) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, b)
+}
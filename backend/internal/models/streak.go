@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Streak day kinds
+const (
+	StreakDayPlayed = "played"
+	StreakDayFrozen = "frozen"
+)
+
+// StreakDay records one calendar day of a user's streak, either a day with at
+// least one completed puzzle or a missed day covered by a streak freeze
+type StreakDay struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	UserID           uint      `gorm:"not null;uniqueIndex:idx_streak_days_user_date" json:"user_id"`
+	Date             time.Time `gorm:"type:date;not null;uniqueIndex:idx_streak_days_user_date" json:"date"` // player's local calendar date
+	Kind             string    `gorm:"size:10;not null" json:"kind"`                                         // played, frozen
+	PuzzlesCompleted int       `gorm:"default:0" json:"puzzles_completed"`
+	CreatedAt        time.Time `json:"created_at"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name for StreakDay model
+func (StreakDay) TableName() string {
+	return "streak_days"
+}
@@ -42,6 +42,7 @@ type UserProfile struct {
 	CurrentStreak        int       `gorm:"default:0" json:"current_streak"`
 	LongestStreak        int       `gorm:"default:0" json:"longest_streak"`
 	LastPuzzleDate       *time.Time `json:"last_puzzle_date,omitempty"`
+	StreakFreezes        int       `gorm:"default:0" json:"streak_freezes"` // missed days that can still be covered
 	
 	// Music preferences
 	MusicEnabled         bool      `gorm:"default:true" json:"music_enabled"`
@@ -50,6 +51,7 @@ type UserProfile struct {
 	// User preferences
 	DifficultyPreference string    `gorm:"size:20;default:'beginner'" json:"difficulty_preference"` // beginner, intermediate, expert
 	Theme                string    `gorm:"size:20;default:'dark'" json:"theme"` // dark, light
+	Timezone             string    `gorm:"size:64;default:'UTC'" json:"timezone"` // IANA name, e.g. America/Los_Angeles
 	
 	CreatedAt            time.Time `json:"created_at"`
 	UpdatedAt            time.Time `json:"updated_at"`
@@ -72,6 +74,7 @@ func (u *User) AfterCreate(tx *gorm.DB) error {
 		MusicVolume:          70,
 		DifficultyPreference: "beginner",
 		Theme:                "dark",
+		Timezone:             "UTC",
 	}
 	return tx.Create(&profile).Error
 }
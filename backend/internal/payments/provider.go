@@ -0,0 +1,50 @@
+package payments
+
+import (
+	"errors"
+	"time"
+)
+
+// EventRenewed is the event status sent when a subscription is charged for another period
+const EventRenewed = "renewed"
+
+// ErrInvalidSignature is returned when a webhook payload fails verification
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// ErrMissingPeriodEnd is returned for a renewal event that does not say which period it paid for
+var ErrMissingPeriodEnd = errors.New("renewal event has no period end")
+
+// CheckoutRequest describes what the user is about to pay for
+type CheckoutRequest struct {
+	UserID       uint
+	PuzzlePackID uint
+	Description  string
+	AmountUSD    float64
+	Currency     string
+}
+
+// CheckoutSession is a payment started with a provider
+type CheckoutSession struct {
+	TransactionID string // provider reference, unique across purchases
+	CheckoutURL   string // where the client completes the payment
+}
+
+// Event is a verified payment status change reported by a provider webhook
+type Event struct {
+	TransactionID string     `json:"transaction_id"`
+	Status        string     `json:"status"`               // completed, failed, refunded, renewed
+	PeriodEnd     *time.Time `json:"period_end,omitempty"` // end of the period a renewal paid for
+}
+
+// Provider is a payment backend such as Stripe or an app store.
+// A payment moves through checkout, webhook verification and optionally refund.
+type Provider interface {
+	// Name identifies the provider in purchases and webhook URLs
+	Name() string
+	// CreateCheckout starts a payment and returns its transaction reference
+	CreateCheckout(req CheckoutRequest) (*CheckoutSession, error)
+	// VerifyEvent authenticates a webhook payload and decodes it
+	VerifyEvent(payload []byte, signature string) (*Event, error)
+	// Refund returns the money for a completed transaction
+	Refund(transactionID string, amountUSD float64) error
+}
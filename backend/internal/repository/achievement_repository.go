@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"hh_puzzle/internal/models"
+)
+
+// AchievementRepository defines methods for achievement data access
+type AchievementRepository interface {
+	WithTx(tx *Tx) AchievementRepository
+	Create(achievement *models.Achievement) error
+	FindActive() ([]models.Achievement, error)
+	FindUnawarded(userID uint) ([]models.Achievement, error)
+	FindAwardedByUser(userID uint) ([]models.UserAchievement, error)
+	Award(userID, achievementID uint, puzzleID *uint, awardedAt time.Time) (bool, error)
+	CountCompletedPuzzles(userID uint, criteria models.AchievementCriteria) (int64, error)
+	CountPuzzles(criteria models.AchievementCriteria) (int64, error)
+}
+
+type achievementRepository struct {
+	db *gorm.DB
+}
+
+// NewAchievementRepository creates a new achievement repository
+func NewAchievementRepository(db *gorm.DB) AchievementRepository {
+	return &achievementRepository{db: db}
+}
+
+// WithTx returns a copy of the repository that runs inside the transaction
+func (r *achievementRepository) WithTx(tx *Tx) AchievementRepository {
+	return &achievementRepository{db: tx.db}
+}
+
+func (r *achievementRepository) Create(achievement *models.Achievement) error {
+	return r.db.Create(achievement).Error
+}
+
+func (r *achievementRepository) FindActive() ([]models.Achievement, error) {
+	var achievements []models.Achievement
+	err := r.db.Where("is_active = ?", true).Order("id ASC").Find(&achievements).Error
+	return achievements, err
+}
+
+// FindUnawarded returns active achievements the user has not earned yet
+func (r *achievementRepository) FindUnawarded(userID uint) ([]models.Achievement, error) {
+	var achievements []models.Achievement
+	err := r.db.Where("is_active = ?", true).
+		Where("NOT EXISTS (SELECT 1 FROM user_achievements u WHERE u.achievement_id = achievements.id AND u.user_id = ?)", userID).
+		Order("id ASC").
+		Find(&achievements).Error
+	return achievements, err
+}
+
+func (r *achievementRepository) FindAwardedByUser(userID uint) ([]models.UserAchievement, error) {
+	var awards []models.UserAchievement
+	err := r.db.Where("user_id = ?", userID).Order("awarded_at ASC").Find(&awards).Error
+	return awards, err
+}
+
+// Award records an earned achievement. It reports false when the user already had it.
+func (r *achievementRepository) Award(userID, achievementID uint, puzzleID *uint, awardedAt time.Time) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.UserAchievement{
+		UserID:        userID,
+		AchievementID: achievementID,
+		PuzzleID:      puzzleID,
+		AwardedAt:     awardedAt,
+	})
+	return result.RowsAffected > 0, result.Error
+}
+
+// CountCompletedPuzzles counts the distinct puzzles matching the criteria filters that the user has completed
+func (r *achievementRepository) CountCompletedPuzzles(userID uint, criteria models.AchievementCriteria) (int64, error) {
+	var count int64
+	query := r.db.Model(&models.PuzzleAttempt{}).
+		Joins("JOIN puzzles ON puzzles.id = puzzle_attempts.puzzle_id AND puzzles.deleted_at IS NULL").
+		Where("puzzle_attempts.user_id = ? AND puzzle_attempts.is_completed = ?", userID, true)
+	err := filterPuzzles(query, criteria).
+		Distinct("puzzle_attempts.puzzle_id").
+		Count(&count).Error
+	return count, err
+}
+
+// CountPuzzles counts the puzzles matching the criteria filters
+func (r *achievementRepository) CountPuzzles(criteria models.AchievementCriteria) (int64, error) {
+	var count int64
+	err := filterPuzzles(r.db.Model(&models.Puzzle{}), criteria).Count(&count).Error
+	return count, err
+}
+
+// filterPuzzles restricts a query joined to puzzles by the criteria filters
+func filterPuzzles(query *gorm.DB, criteria models.AchievementCriteria) *gorm.DB {
+	if criteria.Difficulty != "" {
+		query = query.Where("puzzles.difficulty = ?", criteria.Difficulty)
+	}
+	if criteria.Decade != "" {
+		query = query.Where("puzzles.decade = ?", criteria.Decade)
+	}
+	if criteria.Region != "" {
+		query = query.Where("puzzles.region = ?", criteria.Region)
+	}
+	if criteria.Subgenre != "" {
+		query = query.Where("puzzles.subgenre = ?", criteria.Subgenre)
+	}
+	return query
+}
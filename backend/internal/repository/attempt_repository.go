@@ -4,17 +4,32 @@ import (
 "errors"
 
 "gorm.io/gorm"
+	"gorm.io/gorm/clause"
 "hh_puzzle/internal/models"
 )
 
+// CompletionTimeStats summarises how long solvers took on a puzzle
+type CompletionTimeStats struct {
+	PuzzleID      uint
+	Samples       int
+	MedianSeconds float64
+}
+
 // AttemptRepository defines methods for puzzle attempt data access
 type AttemptRepository interface {
+	WithTx(tx *Tx) AttemptRepository
 Create(attempt *models.PuzzleAttempt) error
 FindByID(id uint) (*models.PuzzleAttempt, error)
-FindByUserAndPuzzle(userID, puzzleID uint) (*models.PuzzleAttempt, error)
+	FindLatestRun(userID, puzzleID uint) (*models.PuzzleAttempt, error)
+	FindByShareCodeForUpdate(code string) (*models.PuzzleAttempt, error)
 FindByUser(userID uint) ([]models.PuzzleAttempt, error)
 Update(attempt *models.PuzzleAttempt) error
+	MarkCompleted(attempt *models.PuzzleAttempt) (bool, error)
+	UpdateInProgress(attempt *models.PuzzleAttempt, fromVersion int, columns ...string) (bool, error)
+	Share(attempt *models.PuzzleAttempt, code string) (bool, error)
 GetUserCompletedCount(userID uint) (int64, error)
+	CountCompletedInPack(userID, packID uint) (int64, error)
+	FindCompletionTimeStats(minSamples int) ([]CompletionTimeStats, error)
 }
 
 type attemptRepository struct {
@@ -26,6 +41,11 @@ func NewAttemptRepository(db *gorm.DB) AttemptRepository {
 return &attemptRepository{db: db}
 }
 
+// WithTx returns a copy of the repository that runs inside the transaction
+func (r *attemptRepository) WithTx(tx *Tx) AttemptRepository {
+	return &attemptRepository{db: tx.db}
+}
+
 func (r *attemptRepository) Create(attempt *models.PuzzleAttempt) error {
 return r.db.Create(attempt).Error
 }
@@ -42,9 +62,28 @@ return nil, err
 return &attempt, nil
 }
 
-func (r *attemptRepository) FindByUserAndPuzzle(userID, puzzleID uint) (*models.PuzzleAttempt, error) {
+// FindLatestRun returns the user's run of a puzzle with the highest run number
+func (r *attemptRepository) FindLatestRun(userID, puzzleID uint) (*models.PuzzleAttempt, error) {
+	var attempt models.PuzzleAttempt
+	err := r.db.Where("user_id = ? AND puzzle_id = ?", userID, puzzleID).
+		Order("run_number DESC").
+		First(&attempt).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("attempt not found")
+		}
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// FindByShareCodeForUpdate loads the attempt shared with a code and locks its
+// row until the surrounding transaction ends, so members join one at a time
+func (r *attemptRepository) FindByShareCodeForUpdate(code string) (*models.PuzzleAttempt, error) {
 	var attempt models.PuzzleAttempt
-	err := r.db.Where("user_id = ? AND puzzle_id = ?", userID, puzzleID).First(&attempt).Error
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("share_code = ? AND is_shared = ?", code, true).
+		First(&attempt).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("attempt not found")
@@ -64,8 +103,73 @@ func (r *attemptRepository) Update(attempt *models.PuzzleAttempt) error {
 return r.db.Save(attempt).Error
 }
 
+// MarkCompleted saves a submitted attempt only if it is not completed yet. It
+// reports false when another submit completed it first.
+func (r *attemptRepository) MarkCompleted(attempt *models.PuzzleAttempt) (bool, error) {
+	result := r.db.Model(attempt).
+		Where("is_completed = ?", false).
+		Select("is_completed", "completed_at", "current_state", "version", "cell_versions", "cell_authors", "completion_seconds", "client_completion_seconds", "time_mismatch", "hints_used", "points_earned", "accuracy_percentage", "scoring_policy_version", "score_breakdown").
+		Updates(attempt)
+	return result.RowsAffected > 0, result.Error
+}
+
+// UpdateInProgress saves the given columns and the state version of an
+// attempt that is not completed and still at fromVersion. It reports false
+// when the attempt was completed or changed in the meantime, so a late
+// progress save can neither reopen a submitted attempt nor overwrite a newer one.
+func (r *attemptRepository) UpdateInProgress(attempt *models.PuzzleAttempt, fromVersion int, columns ...string) (bool, error) {
+	result := r.db.Model(attempt).
+		Where("is_completed = ? AND version = ?", false, fromVersion).
+		Select(append(columns, "version", "cell_versions", "cell_authors")).
+		Updates(attempt)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Share marks an attempt that is not completed as shared with the code. It
+// reports false when the attempt was completed or shared in the meantime.
+func (r *attemptRepository) Share(attempt *models.PuzzleAttempt, code string) (bool, error) {
+	result := r.db.Model(attempt).
+		Where("is_completed = ? AND is_shared = ?", false, false).
+		Updates(map[string]interface{}{"is_shared": true, "share_code": code})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+
+	attempt.IsShared = true
+	attempt.ShareCode = &code
+	return true, nil
+}
+
+// GetUserCompletedCount counts the distinct puzzles the user has completed
 func (r *attemptRepository) GetUserCompletedCount(userID uint) (int64, error) {
 var count int64
-err := r.db.Model(&models.PuzzleAttempt{}).Where("user_id = ? AND is_completed = ?", userID, true).Count(&count).Error
+	err := r.db.Model(&models.PuzzleAttempt{}).Where("user_id = ? AND is_completed = ?", userID, true).Distinct("puzzle_id").Count(&count).Error
 return count, err
-}
\ No newline at end of file
+}
+
+// CountCompletedInPack counts the distinct puzzles of a pack the user has completed
+func (r *attemptRepository) CountCompletedInPack(userID, packID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.PuzzleAttempt{}).
+		Joins("JOIN puzzles ON puzzles.id = puzzle_attempts.puzzle_id AND puzzles.deleted_at IS NULL").
+		Where("puzzle_attempts.user_id = ? AND puzzle_attempts.is_completed = ? AND puzzles.puzzle_pack_id = ?", userID, true, packID).
+		Distinct("puzzle_attempts.puzzle_id").
+		Count(&count).Error
+	return count, err
+}
+
+// FindCompletionTimeStats returns the median completion time of fully correct
+// first runs for every puzzle solved at least minSamples times. Replays are
+// left out since the solver already knows the answers, and shared attempts
+// since several players solved them together.
+func (r *attemptRepository) FindCompletionTimeStats(minSamples int) ([]CompletionTimeStats, error) {
+	var stats []CompletionTimeStats
+	err := r.db.Model(&models.PuzzleAttempt{}).
+		Select("puzzle_id, COUNT(*) AS samples, PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY completion_seconds) AS median_seconds").
+		Where("is_completed = ? AND accuracy_percentage = ? AND completion_seconds > 0", true, 100).
+		Where("run_number = ? AND is_shared = ?", 1, false).
+		Group("puzzle_id").
+		Having("COUNT(*) >= ?", minSamples).
+		Scan(&stats).Error
+	return stats, err
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"hh_puzzle/internal/models"
+)
+
+// PuzzleBestRepository defines methods for best time data access
+type PuzzleBestRepository interface {
+	WithTx(tx *Tx) PuzzleBestRepository
+	RecordRun(attempt *models.PuzzleAttempt, solved bool) (*models.PuzzleBest, error)
+	FindByUserAndPuzzle(userID, puzzleID uint) (*models.PuzzleBest, error)
+	FindByUser(userID uint) ([]models.PuzzleBest, error)
+}
+
+type puzzleBestRepository struct {
+	db *gorm.DB
+}
+
+// NewPuzzleBestRepository creates a new best time repository
+func NewPuzzleBestRepository(db *gorm.DB) PuzzleBestRepository {
+	return &puzzleBestRepository{db: db}
+}
+
+// WithTx returns a copy of the repository that runs inside the transaction
+func (r *puzzleBestRepository) WithTx(tx *Tx) PuzzleBestRepository {
+	return &puzzleBestRepository{db: tx.db}
+}
+
+// RecordRun counts a completed run and keeps its time when it is solved and
+// faster than the best so far. It returns the updated record.
+func (r *puzzleBestRepository) RecordRun(attempt *models.PuzzleAttempt, solved bool) (*models.PuzzleBest, error) {
+	best := &models.PuzzleBest{
+		UserID:        attempt.UserID,
+		PuzzleID:      attempt.PuzzleID,
+		RunsCompleted: 1,
+		UpdatedAt:     time.Now(),
+	}
+	if solved && attempt.CompletionTime != nil {
+		best.BestTime = attempt.CompletionTime
+		best.BestAttemptID = &attempt.ID
+	}
+
+	improves := "excluded.best_time_seconds IS NOT NULL AND (puzzle_bests.best_time_seconds IS NULL OR excluded.best_time_seconds < puzzle_bests.best_time_seconds)"
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "puzzle_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"runs_completed":    gorm.Expr("puzzle_bests.runs_completed + 1"),
+			"best_time_seconds": gorm.Expr("CASE WHEN " + improves + " THEN excluded.best_time_seconds ELSE puzzle_bests.best_time_seconds END"),
+			"best_attempt_id":   gorm.Expr("CASE WHEN " + improves + " THEN excluded.best_attempt_id ELSE puzzle_bests.best_attempt_id END"),
+			"updated_at":        gorm.Expr("excluded.updated_at"),
+		}),
+	}).Create(best).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return r.FindByUserAndPuzzle(attempt.UserID, attempt.PuzzleID)
+}
+
+func (r *puzzleBestRepository) FindByUserAndPuzzle(userID, puzzleID uint) (*models.PuzzleBest, error) {
+	var best models.PuzzleBest
+	err := r.db.Where("user_id = ? AND puzzle_id = ?", userID, puzzleID).First(&best).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("best time not found")
+		}
+		return nil, err
+	}
+	return &best, nil
+}
+
+func (r *puzzleBestRepository) FindByUser(userID uint) ([]models.PuzzleBest, error) {
+	var bests []models.PuzzleBest
+	err := r.db.Where("user_id = ?", userID).Order("updated_at DESC").Find(&bests).Error
+	return bests, err
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"hh_puzzle/internal/models"
+)
+
+// FactRepository defines methods for hip-hop fact data access
+type FactRepository interface {
+	WithTx(tx *Tx) FactRepository
+	FindAll() ([]models.HipHopFact, error)
+	FindByID(id uint) (*models.HipHopFact, error)
+	FindUnlockedByUser(userID uint) ([]models.UserUnlockedFact, error)
+	IsUnlocked(userID, factID uint) (bool, error)
+	FindUnlockable(userID, puzzleID uint, totalPoints, streak int) ([]models.HipHopFact, error)
+	Unlock(userID, factID uint, unlockedAt time.Time) (bool, error)
+}
+
+type factRepository struct {
+	db *gorm.DB
+}
+
+// NewFactRepository creates a new fact repository
+func NewFactRepository(db *gorm.DB) FactRepository {
+	return &factRepository{db: db}
+}
+
+// WithTx returns a copy of the repository that runs inside the transaction
+func (r *factRepository) WithTx(tx *Tx) FactRepository {
+	return &factRepository{db: tx.db}
+}
+
+func (r *factRepository) FindAll() ([]models.HipHopFact, error) {
+	var facts []models.HipHopFact
+	err := r.db.Order("id ASC").Find(&facts).Error
+	return facts, err
+}
+
+func (r *factRepository) FindByID(id uint) (*models.HipHopFact, error) {
+	var fact models.HipHopFact
+	err := r.db.First(&fact, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("fact not found")
+		}
+		return nil, err
+	}
+	return &fact, nil
+}
+
+func (r *factRepository) FindUnlockedByUser(userID uint) ([]models.UserUnlockedFact, error) {
+	var unlocked []models.UserUnlockedFact
+	err := r.db.Where("user_id = ?", userID).Find(&unlocked).Error
+	return unlocked, err
+}
+
+func (r *factRepository) IsUnlocked(userID, factID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.UserUnlockedFact{}).
+		Where("user_id = ? AND fact_id = ?", userID, factID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindUnlockable returns facts the user has not unlocked yet whose unlock
+// condition is met by the given puzzle, point total or streak
+func (r *factRepository) FindUnlockable(userID, puzzleID uint, totalPoints, streak int) ([]models.HipHopFact, error) {
+	var facts []models.HipHopFact
+	err := r.db.
+		Where(r.db.
+			Where("unlock_type = ? AND unlock_value = ?", models.UnlockPuzzleCompletion, puzzleID).
+			Or("unlock_type = ? AND unlock_value <= ?", models.UnlockPointsMilestone, totalPoints).
+			Or("unlock_type = ? AND unlock_value <= ?", models.UnlockStreak, streak)).
+		Where("NOT EXISTS (SELECT 1 FROM user_unlocked_facts u WHERE u.fact_id = hip_hop_facts.id AND u.user_id = ?)", userID).
+		Order("id ASC").
+		Find(&facts).Error
+	return facts, err
+}
+
+// Unlock records an unlocked fact. It reports false when the user already had it.
+func (r *factRepository) Unlock(userID, factID uint, unlockedAt time.Time) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.UserUnlockedFact{
+		UserID:     userID,
+		FactID:     factID,
+		UnlockedAt: unlockedAt,
+	})
+	return result.RowsAffected > 0, result.Error
+}
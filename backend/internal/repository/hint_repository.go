@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"hh_puzzle/internal/models"
+)
+
+// HintRepository defines methods for attempt hint data access
+type HintRepository interface {
+	WithTx(tx *Tx) HintRepository
+	Create(hint *models.AttemptHint) error
+	FindByAttempt(attemptID uint) ([]models.AttemptHint, error)
+	CountByAttempt(attemptID uint) (int64, error)
+}
+
+type hintRepository struct {
+	db *gorm.DB
+}
+
+// NewHintRepository creates a new hint repository
+func NewHintRepository(db *gorm.DB) HintRepository {
+	return &hintRepository{db: db}
+}
+
+// WithTx returns a copy of the repository that runs inside the transaction
+func (r *hintRepository) WithTx(tx *Tx) HintRepository {
+	return &hintRepository{db: tx.db}
+}
+
+func (r *hintRepository) Create(hint *models.AttemptHint) error {
+	return r.db.Create(hint).Error
+}
+
+func (r *hintRepository) FindByAttempt(attemptID uint) ([]models.AttemptHint, error) {
+	var hints []models.AttemptHint
+	err := r.db.Where("attempt_id = ?", attemptID).Order("created_at ASC").Find(&hints).Error
+	return hints, err
+}
+
+func (r *hintRepository) CountByAttempt(attemptID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.AttemptHint{}).Where("attempt_id = ?", attemptID).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"hh_puzzle/internal/models"
+)
+
+// IdempotencyRepository defines methods for idempotency key data access
+type IdempotencyRepository interface {
+	Reserve(record *models.IdempotencyKey) (bool, error)
+	Find(userID uint, key, route string) (*models.IdempotencyKey, error)
+	Complete(record *models.IdempotencyKey) error
+	Delete(record *models.IdempotencyKey) error
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+type idempotencyRepository struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyRepository creates a new idempotency key repository
+func NewIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &idempotencyRepository{db: db}
+}
+
+// Reserve inserts an in-flight record for the key. It reports false when a
+// record for the same user, key and route already exists.
+func (r *idempotencyRepository) Reserve(record *models.IdempotencyKey) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *idempotencyRepository) Find(userID uint, key, route string) (*models.IdempotencyKey, error) {
+	var record models.IdempotencyKey
+	err := r.db.Where("user_id = ? AND key = ? AND route = ?", userID, key, route).
+		First(&record).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("idempotency key not found")
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Complete stores the response for a reserved key
+func (r *idempotencyRepository) Complete(record *models.IdempotencyKey) error {
+	return r.db.Model(record).
+		Select("status_code", "content_type", "response_body", "completed_at").
+		Updates(record).Error
+}
+
+func (r *idempotencyRepository) Delete(record *models.IdempotencyKey) error {
+	return r.db.Delete(record).Error
+}
+
+// DeleteExpired removes keys whose TTL has passed
+func (r *idempotencyRepository) DeleteExpired(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at <= ?", now).Delete(&models.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"hh_puzzle/internal/models"
+)
+
+// LeaderboardStanding is a ranked leaderboard row joined with the player's profile
+type LeaderboardStanding struct {
+	Rank                  int             `json:"rank"`
+	UserID                uint            `json:"user_id"`
+	Username              string          `json:"username"`
+	DisplayName           string          `json:"display_name,omitempty"`
+	AvatarURL             string          `json:"avatar_url,omitempty"`
+	TotalPoints           int             `json:"total_points"`
+	PuzzlesCompleted      int             `json:"puzzles_completed"`
+	AverageCompletionTime *models.Seconds `json:"average_completion_seconds,omitempty"`
+}
+
+// LeaderboardRepository defines methods for leaderboard data access
+type LeaderboardRepository interface {
+	WithTx(tx *Tx) LeaderboardRepository
+	Create(entry *models.Leaderboard) error
+	Update(entry *models.Leaderboard) error
+	FindByUserAndWeek(userID uint, weekStart time.Time) (*models.Leaderboard, error)
+	FindUnrankedWeeksBefore(date time.Time) ([]time.Time, error)
+	RankWeek(weekStart time.Time) error
+	FindWeekStandings(weekStart time.Time, limit, offset int) ([]LeaderboardStanding, error)
+	FindUserWeekStanding(userID uint, weekStart time.Time) (*LeaderboardStanding, error)
+	CountWeek(weekStart time.Time) (int64, error)
+	FindAllTimeStandings(limit, offset int) ([]LeaderboardStanding, error)
+	FindUserAllTimeStanding(userID uint) (*LeaderboardStanding, error)
+	CountAllTime() (int64, error)
+}
+
+type leaderboardRepository struct {
+	db *gorm.DB
+}
+
+// NewLeaderboardRepository creates a new leaderboard repository
+func NewLeaderboardRepository(db *gorm.DB) LeaderboardRepository {
+	return &leaderboardRepository{db: db}
+}
+
+// WithTx returns a copy of the repository that runs inside the transaction
+func (r *leaderboardRepository) WithTx(tx *Tx) LeaderboardRepository {
+	return &leaderboardRepository{db: tx.db}
+}
+
+// weekStandingsQuery ranks a week's entries. Closed weeks keep the rank stored
+// by the ranking job; the current week is ranked live by points.
+const weekStandingsQuery = `
+SELECT
+	COALESCE(l.rank, RANK() OVER (ORDER BY l.total_points DESC)) AS rank,
+	l.user_id, u.username, p.display_name, p.avatar_url,
+	l.total_points, l.puzzles_completed, l.average_completion_seconds AS average_completion_time
+FROM leaderboards l
+JOIN users u ON u.id = l.user_id AND u.deleted_at IS NULL
+LEFT JOIN user_profiles p ON p.user_id = l.user_id
+WHERE l.week_start_date = ?`
+
+// allTimeStandingsQuery ranks every player who has completed a puzzle by lifetime points
+const allTimeStandingsQuery = `
+SELECT
+	RANK() OVER (ORDER BY p.total_points DESC) AS rank,
+	p.user_id, u.username, p.display_name, p.avatar_url,
+	p.total_points, p.puzzles_completed, NULL AS average_completion_time
+FROM user_profiles p
+JOIN users u ON u.id = p.user_id AND u.deleted_at IS NULL
+WHERE p.puzzles_completed > 0`
+
+func (r *leaderboardRepository) Create(entry *models.Leaderboard) error {
+	return r.db.Create(entry).Error
+}
+
+func (r *leaderboardRepository) Update(entry *models.Leaderboard) error {
+	return r.db.Save(entry).Error
+}
+
+func (r *leaderboardRepository) FindByUserAndWeek(userID uint, weekStart time.Time) (*models.Leaderboard, error) {
+	var entry models.Leaderboard
+	err := r.db.Where("user_id = ? AND week_start_date = ?", userID, weekStart).First(&entry).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("leaderboard entry not found")
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *leaderboardRepository) FindUnrankedWeeksBefore(date time.Time) ([]time.Time, error) {
+	var weeks []time.Time
+	err := r.db.Model(&models.Leaderboard{}).
+		Where("rank IS NULL AND week_end_date < ?", date).
+		Distinct().
+		Order("week_start_date ASC").
+		Pluck("week_start_date", &weeks).Error
+	return weeks, err
+}
+
+func (r *leaderboardRepository) RankWeek(weekStart time.Time) error {
+	return r.db.Exec(`
+UPDATE leaderboards l SET rank = ranked.rank, updated_at = NOW()
+FROM (
+	SELECT id, RANK() OVER (ORDER BY total_points DESC) AS rank
+	FROM leaderboards
+	WHERE week_start_date = ?
+) ranked
+WHERE l.id = ranked.id`, weekStart).Error
+}
+
+func (r *leaderboardRepository) FindWeekStandings(weekStart time.Time, limit, offset int) ([]LeaderboardStanding, error) {
+	var standings []LeaderboardStanding
+	err := r.db.Raw("SELECT * FROM ("+weekStandingsQuery+") s ORDER BY s.rank ASC, s.user_id ASC LIMIT ? OFFSET ?", weekStart, limit, offset).
+		Scan(&standings).Error
+	return standings, err
+}
+
+func (r *leaderboardRepository) FindUserWeekStanding(userID uint, weekStart time.Time) (*LeaderboardStanding, error) {
+	var standings []LeaderboardStanding
+	err := r.db.Raw("SELECT * FROM ("+weekStandingsQuery+") s WHERE s.user_id = ?", weekStart, userID).
+		Scan(&standings).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(standings) == 0 {
+		return nil, errors.New("leaderboard entry not found")
+	}
+	return &standings[0], nil
+}
+
+func (r *leaderboardRepository) CountWeek(weekStart time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Leaderboard{}).
+		Joins("JOIN users ON users.id = leaderboards.user_id AND users.deleted_at IS NULL").
+		Where("leaderboards.week_start_date = ?", weekStart).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *leaderboardRepository) FindAllTimeStandings(limit, offset int) ([]LeaderboardStanding, error) {
+	var standings []LeaderboardStanding
+	err := r.db.Raw("SELECT * FROM ("+allTimeStandingsQuery+") s ORDER BY s.rank ASC, s.user_id ASC LIMIT ? OFFSET ?", limit, offset).
+		Scan(&standings).Error
+	return standings, err
+}
+
+func (r *leaderboardRepository) FindUserAllTimeStanding(userID uint) (*LeaderboardStanding, error) {
+	var standings []LeaderboardStanding
+	err := r.db.Raw("SELECT * FROM ("+allTimeStandingsQuery+") s WHERE s.user_id = ?", userID).
+		Scan(&standings).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(standings) == 0 {
+		return nil, errors.New("leaderboard entry not found")
+	}
+	return &standings[0], nil
+}
+
+func (r *leaderboardRepository) CountAllTime() (int64, error) {
+	var count int64
+	err := r.db.Model(&models.UserProfile{}).
+		Joins("JOIN users ON users.id = user_profiles.user_id AND users.deleted_at IS NULL").
+		Where("user_profiles.puzzles_completed > 0").
+		Count(&count).Error
+	return count, err
+}
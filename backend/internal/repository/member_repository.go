@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"hh_puzzle/internal/models"
+)
+
+// AttemptMemberRepository defines methods for shared attempt member data access
+type AttemptMemberRepository interface {
+	WithTx(tx *Tx) AttemptMemberRepository
+	Add(member *models.AttemptMember) (bool, error)
+	FindByAttempt(attemptID uint) ([]models.AttemptMember, error)
+	IsMember(attemptID, userID uint) (bool, error)
+	HasCompletedPuzzle(userID, puzzleID uint) (bool, error)
+	UpdateShare(member *models.AttemptMember) error
+}
+
+type attemptMemberRepository struct {
+	db *gorm.DB
+}
+
+// NewAttemptMemberRepository creates a new attempt member repository
+func NewAttemptMemberRepository(db *gorm.DB) AttemptMemberRepository {
+	return &attemptMemberRepository{db: db}
+}
+
+// WithTx returns a copy of the repository that runs inside the transaction
+func (r *attemptMemberRepository) WithTx(tx *Tx) AttemptMemberRepository {
+	return &attemptMemberRepository{db: tx.db}
+}
+
+// Add adds a member to an attempt. It reports false when the user already is one.
+func (r *attemptMemberRepository) Add(member *models.AttemptMember) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "attempt_id"}, {Name: "user_id"}},
+		DoNothing: true,
+	}).Create(member)
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindByAttempt returns an attempt's members in the order they joined
+func (r *attemptMemberRepository) FindByAttempt(attemptID uint) ([]models.AttemptMember, error) {
+	var members []models.AttemptMember
+	err := r.db.Where("attempt_id = ?", attemptID).Order("joined_at ASC, id ASC").Find(&members).Error
+	return members, err
+}
+
+func (r *attemptMemberRepository) IsMember(attemptID, userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.AttemptMember{}).
+		Where("attempt_id = ? AND user_id = ?", attemptID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// HasCompletedPuzzle reports whether the user was a member of a completed
+// shared attempt of the puzzle
+func (r *attemptMemberRepository) HasCompletedPuzzle(userID, puzzleID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.AttemptMember{}).
+		Joins("JOIN puzzle_attempts ON puzzle_attempts.id = attempt_members.attempt_id").
+		Where("attempt_members.user_id = ? AND puzzle_attempts.puzzle_id = ? AND puzzle_attempts.is_completed = ?", userID, puzzleID, true).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// UpdateShare saves the member's correct cells and points from a submit
+func (r *attemptMemberRepository) UpdateShare(member *models.AttemptMember) error {
+	return r.db.Model(member).Select("correct_cells", "points_earned").Updates(member).Error
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"hh_puzzle/internal/models"
+)
+
+// MusicRepository defines methods for music track data access
+type MusicRepository interface {
+	Create(track *models.MusicTrack) error
+	FindByID(id uint) (*models.MusicTrack, error)
+	FindTracks(mood string, active *bool) ([]models.MusicTrack, error)
+	FindActiveByMoods(moods []string) ([]models.MusicTrack, error)
+	Update(track *models.MusicTrack) error
+	Delete(id uint) error
+	IncrementPlayCount(id uint) error
+}
+
+type musicRepository struct {
+	db *gorm.DB
+}
+
+// NewMusicRepository creates a new music repository
+func NewMusicRepository(db *gorm.DB) MusicRepository {
+	return &musicRepository{db: db}
+}
+
+func (r *musicRepository) Create(track *models.MusicTrack) error {
+	// is_active has a database default, so a false value must be written explicitly
+	return r.db.Select("*").Omit("id").Create(track).Error
+}
+
+func (r *musicRepository) FindByID(id uint) (*models.MusicTrack, error) {
+	var track models.MusicTrack
+	err := r.db.First(&track, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("track not found")
+		}
+		return nil, err
+	}
+	return &track, nil
+}
+
+// FindTracks lists tracks, optionally filtered by mood and active flag
+func (r *musicRepository) FindTracks(mood string, active *bool) ([]models.MusicTrack, error) {
+	var tracks []models.MusicTrack
+	query := r.db.Model(&models.MusicTrack{})
+
+	if mood != "" {
+		query = query.Where("mood = ?", mood)
+	}
+	if active != nil {
+		query = query.Where("is_active = ?", *active)
+	}
+
+	err := query.Order("title ASC").Find(&tracks).Error
+	return tracks, err
+}
+
+func (r *musicRepository) FindActiveByMoods(moods []string) ([]models.MusicTrack, error) {
+	var tracks []models.MusicTrack
+	query := r.db.Where("is_active = ?", true)
+	if len(moods) > 0 {
+		query = query.Where("mood IN ?", moods)
+	}
+	err := query.Find(&tracks).Error
+	return tracks, err
+}
+
+func (r *musicRepository) Update(track *models.MusicTrack) error {
+	return r.db.Save(track).Error
+}
+
+func (r *musicRepository) Delete(id uint) error {
+	result := r.db.Delete(&models.MusicTrack{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("track not found")
+	}
+	return nil
+}
+
+// IncrementPlayCount adds one play in a single UPDATE so concurrent reports are not lost
+func (r *musicRepository) IncrementPlayCount(id uint) error {
+	result := r.db.Model(&models.MusicTrack{}).
+		Where("id = ?", id).
+		UpdateColumn("play_count", gorm.Expr("play_count + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("track not found")
+	}
+	return nil
+}
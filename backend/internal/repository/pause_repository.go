@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"hh_puzzle/internal/models"
+)
+
+// PauseRepository defines methods for attempt pause data access
+type PauseRepository interface {
+	WithTx(tx *Tx) PauseRepository
+	Open(pause *models.AttemptPause) (bool, error)
+	CloseOpen(attemptID uint, resumedAt time.Time) (bool, error)
+	IsPaused(attemptID uint) (bool, error)
+	FindByAttempt(attemptID uint) ([]models.AttemptPause, error)
+}
+
+type pauseRepository struct {
+	db *gorm.DB
+}
+
+// NewPauseRepository creates a new pause repository
+func NewPauseRepository(db *gorm.DB) PauseRepository {
+	return &pauseRepository{db: db}
+}
+
+// WithTx returns a copy of the repository that runs inside the transaction
+func (r *pauseRepository) WithTx(tx *Tx) PauseRepository {
+	return &pauseRepository{db: tx.db}
+}
+
+// Open starts a pause. It reports false when the attempt is already paused.
+func (r *pauseRepository) Open(pause *models.AttemptPause) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "attempt_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "resumed_at IS NULL"}}},
+		DoNothing:   true,
+	}).Create(pause)
+	return result.RowsAffected > 0, result.Error
+}
+
+// CloseOpen ends the attempt's open pause. It reports false when the attempt
+// was not paused.
+func (r *pauseRepository) CloseOpen(attemptID uint, resumedAt time.Time) (bool, error) {
+	result := r.db.Model(&models.AttemptPause{}).
+		Where("attempt_id = ? AND resumed_at IS NULL", attemptID).
+		Update("resumed_at", resumedAt)
+	return result.RowsAffected > 0, result.Error
+}
+
+// IsPaused reports whether the attempt has an open pause
+func (r *pauseRepository) IsPaused(attemptID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.AttemptPause{}).
+		Where("attempt_id = ? AND resumed_at IS NULL", attemptID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *pauseRepository) FindByAttempt(attemptID uint) ([]models.AttemptPause, error) {
+	var pauses []models.AttemptPause
+	err := r.db.Where("attempt_id = ?", attemptID).Order("paused_at ASC").Find(&pauses).Error
+	return pauses, err
+}
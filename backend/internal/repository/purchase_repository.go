@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"hh_puzzle/internal/models"
+)
+
+// PurchaseRepository defines methods for purchase data access
+type PurchaseRepository interface {
+	Create(purchase *models.Purchase) error
+	FindByID(id uint) (*models.Purchase, error)
+	FindByTransactionID(provider, transactionID string) (*models.Purchase, error)
+	FindByUser(userID uint) ([]models.Purchase, error)
+	FindSubscriptionsByUser(userID uint) ([]models.Purchase, error)
+	Update(purchase *models.Purchase) error
+	UpdateFromStatus(purchase *models.Purchase, fromStatus string) (bool, error)
+	UpdateSubscription(purchase *models.Purchase, fromStatus string, fromEnd *time.Time) (bool, error)
+	HasCompletedPurchase(userID, packID uint) (bool, error)
+	HasActiveSubscription(userID, packID uint, now, graceCutoff time.Time) (bool, error)
+	ExpireSubscriptions(now, graceCutoff time.Time) (int64, error)
+}
+
+type purchaseRepository struct {
+	db *gorm.DB
+}
+
+// NewPurchaseRepository creates a new purchase repository
+func NewPurchaseRepository(db *gorm.DB) PurchaseRepository {
+	return &purchaseRepository{db: db}
+}
+
+func (r *purchaseRepository) Create(purchase *models.Purchase) error {
+	return r.db.Create(purchase).Error
+}
+
+func (r *purchaseRepository) FindByID(id uint) (*models.Purchase, error) {
+	var purchase models.Purchase
+	err := r.db.Preload("PuzzlePack").First(&purchase, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("purchase not found")
+		}
+		return nil, err
+	}
+	return &purchase, nil
+}
+
+func (r *purchaseRepository) FindByTransactionID(provider, transactionID string) (*models.Purchase, error) {
+	var purchase models.Purchase
+	err := r.db.Preload("PuzzlePack").
+		Where("payment_provider = ? AND transaction_id = ?", provider, transactionID).
+		First(&purchase).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("purchase not found")
+		}
+		return nil, err
+	}
+	return &purchase, nil
+}
+
+func (r *purchaseRepository) FindByUser(userID uint) ([]models.Purchase, error) {
+	var purchases []models.Purchase
+	err := r.db.Preload("PuzzlePack").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&purchases).Error
+	return purchases, err
+}
+
+// FindSubscriptionsByUser returns the user's paid subscriptions, newest first
+func (r *purchaseRepository) FindSubscriptionsByUser(userID uint) ([]models.Purchase, error) {
+	var purchases []models.Purchase
+	err := r.db.Preload("PuzzlePack").
+		Where("user_id = ? AND is_subscription = ? AND status = ?", userID, true, models.PurchaseStatusCompleted).
+		Order("subscription_end_date DESC").
+		Find(&purchases).Error
+	return purchases, err
+}
+
+func (r *purchaseRepository) Update(purchase *models.Purchase) error {
+	return r.db.Save(purchase).Error
+}
+
+// UpdateFromStatus saves the purchase's status and subscription fields only if
+// its stored status is still fromStatus. It reports whether the row changed,
+// so concurrent webhooks cannot apply the same transition twice.
+func (r *purchaseRepository) UpdateFromStatus(purchase *models.Purchase, fromStatus string) (bool, error) {
+	result := r.db.Model(purchase).
+		Where("status = ?", fromStatus).
+		Select("status", "subscription_start_date", "subscription_end_date", "subscription_status", "updated_at").
+		Updates(purchase)
+	return result.RowsAffected > 0, result.Error
+}
+
+// UpdateSubscription saves the subscription's status and end date only if the
+// stored ones are still fromStatus and fromEnd. It reports whether the row
+// changed, so a renewal and a cancellation cannot overwrite each other.
+func (r *purchaseRepository) UpdateSubscription(purchase *models.Purchase, fromStatus string, fromEnd *time.Time) (bool, error) {
+	query := r.db.Model(purchase).Where("status = ? AND subscription_status = ?", models.PurchaseStatusCompleted, fromStatus)
+	if fromEnd == nil {
+		query = query.Where("subscription_end_date IS NULL")
+	} else {
+		query = query.Where("subscription_end_date = ?", *fromEnd)
+	}
+
+	result := query.
+		Select("subscription_end_date", "subscription_status", "updated_at").
+		Updates(purchase)
+	return result.RowsAffected > 0, result.Error
+}
+
+// HasCompletedPurchase reports whether the user bought the pack outright
+func (r *purchaseRepository) HasCompletedPurchase(userID, packID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Purchase{}).
+		Where("user_id = ? AND puzzle_pack_id = ? AND status = ?", userID, packID, models.PurchaseStatusCompleted).
+		Where("is_subscription = ?", false).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// HasActiveSubscription reports whether the user has a subscription covering
+// the pack. Active subscriptions keep access until graceCutoff passes their end
+// date; cancelled ones only until the end date itself. Subscriptions without a
+// pack cover every pack.
+func (r *purchaseRepository) HasActiveSubscription(userID, packID uint, now, graceCutoff time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Purchase{}).
+		Where("user_id = ? AND is_subscription = ? AND status = ?", userID, true, models.PurchaseStatusCompleted).
+		Where("(subscription_status = ? AND subscription_end_date > ?) OR (subscription_status = ? AND subscription_end_date > ?)",
+			models.SubscriptionStatusActive, graceCutoff, models.SubscriptionStatusCancelled, now).
+		Where("puzzle_pack_id IS NULL OR puzzle_pack_id = ?", packID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ExpireSubscriptions marks subscriptions whose access has run out as expired
+// and returns how many changed
+func (r *purchaseRepository) ExpireSubscriptions(now, graceCutoff time.Time) (int64, error) {
+	result := r.db.Model(&models.Purchase{}).
+		Where("is_subscription = ?", true).
+		Where("(subscription_status = ? AND subscription_end_date <= ?) OR (subscription_status = ? AND subscription_end_date <= ?)",
+			models.SubscriptionStatusActive, graceCutoff, models.SubscriptionStatusCancelled, now).
+		Updates(map[string]interface{}{
+			"subscription_status": models.SubscriptionStatusExpired,
+			"updated_at":          now,
+		})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"hh_puzzle/internal/models"
+)
+
+// PuzzlePackRepository defines methods for puzzle pack data access
+type PuzzlePackRepository interface {
+	FindActive(categoryType, categoryValue string) ([]models.PuzzlePack, error)
+	FindByID(id uint) (*models.PuzzlePack, error)
+	CountPuzzles(packIDs []uint) (map[uint]int, error)
+}
+
+type puzzlePackRepository struct {
+	db *gorm.DB
+}
+
+// NewPuzzlePackRepository creates a new puzzle pack repository
+func NewPuzzlePackRepository(db *gorm.DB) PuzzlePackRepository {
+	return &puzzlePackRepository{db: db}
+}
+
+func (r *puzzlePackRepository) FindActive(categoryType, categoryValue string) ([]models.PuzzlePack, error) {
+	var packs []models.PuzzlePack
+	query := r.db.Where("is_active = ?", true)
+
+	if categoryType != "" {
+		query = query.Where("category_type = ?", categoryType)
+	}
+	if categoryValue != "" {
+		query = query.Where("category_value = ?", categoryValue)
+	}
+
+	err := query.Order("created_at DESC").Find(&packs).Error
+	return packs, err
+}
+
+func (r *puzzlePackRepository) FindByID(id uint) (*models.PuzzlePack, error) {
+	var pack models.PuzzlePack
+	err := r.db.Preload("Puzzles", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Where("is_active = ?", true).First(&pack, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("puzzle pack not found")
+		}
+		return nil, err
+	}
+	return &pack, nil
+}
+
+// CountPuzzles returns the number of puzzles in each pack
+func (r *puzzlePackRepository) CountPuzzles(packIDs []uint) (map[uint]int, error) {
+	var rows []struct {
+		PuzzlePackID uint
+		Count        int
+	}
+	err := r.db.Model(&models.Puzzle{}).
+		Select("puzzle_pack_id, COUNT(*) AS count").
+		Where("puzzle_pack_id IN ?", packIDs).
+		Group("puzzle_pack_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		counts[row.PuzzlePackID] = row.Count
+	}
+	return counts, nil
+}
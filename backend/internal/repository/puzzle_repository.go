@@ -13,6 +13,10 @@ type PuzzleRepository interface {
 	Create(puzzle *models.Puzzle) error
 	FindByID(id uint) (*models.Puzzle, error)
 	FindDailyChallenge(date time.Time) (*models.Puzzle, error)
+	FindDailyChallengesBetween(start, end time.Time) ([]models.Puzzle, error)
+	FindDailyCandidates() ([]models.Puzzle, error)
+	AssignDailyChallenge(puzzleID uint, date time.Time) error
+	UpdateParTime(puzzleID uint, parTime models.Seconds, samples int) error
 	FindByFilters(difficulty, decade, region string, limit, offset int) ([]models.Puzzle, error)
 	Update(puzzle *models.Puzzle) error
 	Delete(id uint) error
@@ -56,9 +60,61 @@ func (r *puzzleRepository) FindDailyChallenge(date time.Time) (*models.Puzzle, e
 	return &puzzle, nil
 }
 
+// FindDailyChallengesBetween returns the daily challenges scheduled from start to end inclusive
+func (r *puzzleRepository) FindDailyChallengesBetween(start, end time.Time) ([]models.Puzzle, error) {
+	var puzzles []models.Puzzle
+	err := r.db.Select("id", "title", "difficulty", "decade", "region", "daily_challenge_date").
+		Where("is_daily_challenge = ? AND daily_challenge_date BETWEEN DATE(?) AND DATE(?)", true, start, end).
+		Order("daily_challenge_date ASC").
+		Find(&puzzles).Error
+	return puzzles, err
+}
+
+// FindDailyCandidates returns free puzzles that have never been a daily
+// challenge and that nobody has attempted yet
+func (r *puzzleRepository) FindDailyCandidates() ([]models.Puzzle, error) {
+	var puzzles []models.Puzzle
+	err := r.db.Select("id", "title", "difficulty", "decade", "region").
+		Where("daily_challenge_date IS NULL AND puzzle_pack_id IS NULL").
+		Where("NOT EXISTS (SELECT 1 FROM puzzle_attempts a WHERE a.puzzle_id = puzzles.id)").
+		Order("created_at ASC, id ASC").
+		Find(&puzzles).Error
+	return puzzles, err
+}
+
+// AssignDailyChallenge schedules a puzzle for a date. It fails if the puzzle
+// is already scheduled or another puzzle holds the date (unique index).
+func (r *puzzleRepository) AssignDailyChallenge(puzzleID uint, date time.Time) error {
+	// UpdateColumns skips the grid validation hooks, which need the full puzzle
+	result := r.db.Model(&models.Puzzle{}).
+		Where("id = ? AND daily_challenge_date IS NULL", puzzleID).
+		UpdateColumns(map[string]interface{}{
+			"is_daily_challenge":   true,
+			"daily_challenge_date": date,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("puzzle already scheduled")
+	}
+	return nil
+}
+
+// UpdateParTime stores a par time derived from the given number of completions
+func (r *puzzleRepository) UpdateParTime(puzzleID uint, parTime models.Seconds, samples int) error {
+	// UpdateColumns skips the grid validation hooks, which need the full puzzle
+	return r.db.Model(&models.Puzzle{}).
+		Where("id = ?", puzzleID).
+		UpdateColumns(map[string]interface{}{
+			"par_time_seconds": parTime,
+			"par_time_samples": samples,
+		}).Error
+}
+
 func (r *puzzleRepository) FindByFilters(difficulty, decade, region string, limit, offset int) ([]models.Puzzle, error) {
 	var puzzles []models.Puzzle
-	query := r.db.Model(&models.Puzzle{})
+	query := r.db.Model(&models.Puzzle{}).Preload("PuzzlePack")
 
 	if difficulty != "" {
 		query = query.Where("difficulty = ?", difficulty)
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"hh_puzzle/internal/models"
+)
+
+// StreakRepository defines methods for streak history data access
+type StreakRepository interface {
+	WithTx(tx *Tx) StreakRepository
+	RecordPlayed(userID uint, date time.Time) error
+	RecordFrozen(userID uint, dates []time.Time) error
+	FindBetween(userID uint, from, to time.Time) ([]models.StreakDay, error)
+}
+
+type streakRepository struct {
+	db *gorm.DB
+}
+
+// NewStreakRepository creates a new streak repository
+func NewStreakRepository(db *gorm.DB) StreakRepository {
+	return &streakRepository{db: db}
+}
+
+// WithTx returns a copy of the repository that runs inside the transaction
+func (r *streakRepository) WithTx(tx *Tx) StreakRepository {
+	return &streakRepository{db: tx.db}
+}
+
+// RecordPlayed adds a completed puzzle to the user's row for a date. A date
+// that was covered by a freeze becomes a played day.
+func (r *streakRepository) RecordPlayed(userID uint, date time.Time) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "date"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"kind":              models.StreakDayPlayed,
+			"puzzles_completed": gorm.Expr("streak_days.puzzles_completed + 1"),
+		}),
+	}).Create(&models.StreakDay{
+		UserID:           userID,
+		Date:             date,
+		Kind:             models.StreakDayPlayed,
+		PuzzlesCompleted: 1,
+	}).Error
+}
+
+// RecordFrozen marks missed dates as covered by streak freezes
+func (r *streakRepository) RecordFrozen(userID uint, dates []time.Time) error {
+	if len(dates) == 0 {
+		return nil
+	}
+
+	days := make([]models.StreakDay, len(dates))
+	for i, date := range dates {
+		days[i] = models.StreakDay{
+			UserID: userID,
+			Date:   date,
+			Kind:   models.StreakDayFrozen,
+		}
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&days).Error
+}
+
+// FindBetween returns the user's streak days from one date to another inclusive, oldest first
+func (r *streakRepository) FindBetween(userID uint, from, to time.Time) ([]models.StreakDay, error) {
+	var days []models.StreakDay
+	err := r.db.Where("user_id = ? AND date BETWEEN DATE(?) AND DATE(?)", userID, from, to).
+		Order("date ASC").
+		Find(&days).Error
+	return days, err
+}
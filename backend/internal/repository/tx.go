@@ -0,0 +1,29 @@
+package repository
+
+import "gorm.io/gorm"
+
+// Tx is a database transaction that repositories can be bound to with WithTx
+type Tx struct {
+	db *gorm.DB
+}
+
+// TxManager runs work inside database transactions
+type TxManager interface {
+	Transaction(fn func(tx *Tx) error) error
+}
+
+type txManager struct {
+	db *gorm.DB
+}
+
+// NewTxManager creates a new transaction manager
+func NewTxManager(db *gorm.DB) TxManager {
+	return &txManager{db: db}
+}
+
+// Transaction commits when fn returns nil and rolls back on an error or panic
+func (m *txManager) Transaction(fn func(tx *Tx) error) error {
+	return m.db.Transaction(func(db *gorm.DB) error {
+		return fn(&Tx{db: db})
+	})
+}
@@ -4,11 +4,13 @@ import (
 	"errors"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"hh_puzzle/internal/models"
 )
 
 // UserRepository defines methods for user data access
 type UserRepository interface {
+	WithTx(tx *Tx) UserRepository
 	Create(user *models.User) error
 	FindByID(id uint) (*models.User, error)
 	FindByEmail(email string) (*models.User, error)
@@ -16,6 +18,8 @@ type UserRepository interface {
 	Update(user *models.User) error
 	Delete(id uint) error
 	GetWithProfile(id uint) (*models.User, error)
+	FindProfileForUpdate(userID uint) (*models.UserProfile, error)
+	UpdateProfile(profile *models.UserProfile) error
 }
 
 type userRepository struct {
@@ -27,6 +31,11 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
+// WithTx returns a copy of the repository that runs inside the transaction
+func (r *userRepository) WithTx(tx *Tx) UserRepository {
+	return &userRepository{db: tx.db}
+}
+
 func (r *userRepository) Create(user *models.User) error {
 	return r.db.Create(user).Error
 }
@@ -85,4 +94,26 @@ func (r *userRepository) GetWithProfile(id uint) (*models.User, error) {
 		return nil, err
 	}
 	return &user, nil
-}
\ No newline at end of file
+}
+
+// FindProfileForUpdate loads a user's profile and locks its row until the
+// surrounding transaction ends
+func (r *userRepository) FindProfileForUpdate(userID uint) (*models.UserProfile, error) {
+	var profile models.UserProfile
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).
+		First(&profile).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("profile not found")
+		}
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// UpdateProfile saves a profile. Saving the user does not write changes to
+// its already existing profile.
+func (r *userRepository) UpdateProfile(profile *models.UserProfile) error {
+	return r.db.Save(profile).Error
+}
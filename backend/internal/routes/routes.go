@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"hh_puzzle/internal/handlers"
 	"hh_puzzle/internal/middleware"
+	"hh_puzzle/internal/repository"
 )
 
 // SetupRoutes configures all API routes
@@ -12,6 +13,16 @@ func SetupRoutes(
 	userHandler *handlers.UserHandler,
 	puzzleHandler *handlers.PuzzleHandler,
 	attemptHandler *handlers.AttemptHandler,
+	coopHandler *handlers.CoopHandler,
+	leaderboardHandler *handlers.LeaderboardHandler,
+	factHandler *handlers.FactHandler,
+	purchaseHandler *handlers.PurchaseHandler,
+	subscriptionHandler *handlers.SubscriptionHandler,
+	musicHandler *handlers.MusicHandler,
+	achievementHandler *handlers.AchievementHandler,
+	adminHandler *handlers.AdminHandler,
+	idempotencyRepo repository.IdempotencyRepository,
+	adminEmails []string,
 ) *gin.Engine {
 	// Create Gin router with default middleware (logger and recovery)
 	r := gin.Default()
@@ -19,6 +30,9 @@ func SetupRoutes(
 	// Add custom middleware
 	r.Use(middleware.CORSMiddleware())
 
+	// Retry-safe mutating endpoints replay their response for a repeated Idempotency-Key
+	idempotent := middleware.IdempotencyMiddleware(idempotencyRepo, middleware.IdempotencyKeyTTL)
+
 	// Health check
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
@@ -34,9 +48,15 @@ func SetupRoutes(
 	{
 		auth.POST("/register", authHandler.Register)
 		auth.POST("/login", authHandler.Login)
-		auth.POST("/guest", authHandler.CreateGuest)
+		auth.POST("/guest", idempotent, authHandler.CreateGuest)
 	}
 
+	// Public routes - Payment provider callbacks, authenticated by signature
+	r.POST("/api/webhooks/payments/:provider", purchaseHandler.HandleWebhook)
+
+	// Co-op WebSocket, authenticated with the same token sent as a header or query parameter
+	r.GET("/api/attempts/:id/live", middleware.WebSocketAuthMiddleware(), coopHandler.Connect)
+
 	// Protected routes - Require authentication
 	api := r.Group("/api")
 	api.Use(middleware.AuthMiddleware())
@@ -51,6 +71,9 @@ func SetupRoutes(
 			users.PUT("/profile", userHandler.UpdateProfile)
 			users.PUT("/preferences", userHandler.UpdatePreferences)
 			users.GET("/stats", userHandler.GetStats)
+			users.GET("/streak", userHandler.GetStreak)
+			users.POST("/streak/freezes", idempotent, userHandler.BuyStreakFreeze)
+			users.GET("/achievements", achievementHandler.GetAchievements)
 			users.DELETE("/account", userHandler.DeleteAccount)
 		}
 
@@ -72,11 +95,70 @@ func SetupRoutes(
 		// Attempt routes
 		attempts := api.Group("/attempts")
 		{
-			attempts.POST("/start", attemptHandler.StartAttempt)
+			attempts.POST("/start", idempotent, attemptHandler.StartAttempt)
 			attempts.GET("", attemptHandler.GetAttempts)
+			attempts.GET("/bests", attemptHandler.GetBestTimes)
 			attempts.GET("/:id", attemptHandler.GetAttemptByID)
+			attempts.GET("/:id/progress", attemptHandler.GetProgress)
 			attempts.PUT("/:id/progress", attemptHandler.UpdateProgress)
-			attempts.POST("/:id/submit", attemptHandler.SubmitAttempt)
+			attempts.PATCH("/:id/progress", attemptHandler.SyncProgress)
+			attempts.GET("/:id/timer", attemptHandler.GetAttemptTimer)
+			attempts.POST("/:id/pause", attemptHandler.PauseAttempt)
+			attempts.POST("/:id/resume", attemptHandler.ResumeAttempt)
+			attempts.POST("/:id/hints", idempotent, attemptHandler.UseHint)
+			attempts.POST("/:id/submit", idempotent, attemptHandler.SubmitAttempt)
+			attempts.GET("/:id/share", coopHandler.GetSharedAttempt)
+			attempts.POST("/:id/share", coopHandler.ShareAttempt)
+			attempts.POST("/join", coopHandler.JoinAttempt)
+		}
+
+		// Leaderboard routes
+		leaderboards := api.Group("/leaderboards")
+		{
+			leaderboards.GET("/weekly", leaderboardHandler.GetWeekly)
+			leaderboards.GET("/all-time", leaderboardHandler.GetAllTime)
+		}
+
+		// Fact routes
+		facts := api.Group("/facts")
+		{
+			facts.GET("", factHandler.GetFacts)
+			facts.GET("/:id", factHandler.GetFactByID)
+		}
+
+		// Purchase routes
+		purchases := api.Group("/purchases")
+		{
+			purchases.GET("", purchaseHandler.GetPurchases)
+			purchases.POST("", idempotent, purchaseHandler.StartPurchase)
+		}
+
+		// Subscription routes
+		subscriptions := api.Group("/subscriptions")
+		{
+			subscriptions.GET("", subscriptionHandler.GetSubscriptions)
+			subscriptions.DELETE("/:id", subscriptionHandler.CancelSubscription)
+		}
+
+		// Music routes
+		music := api.Group("/music")
+		{
+			music.GET("/tracks", musicHandler.GetTracks)
+			music.POST("/tracks/:id/plays", musicHandler.ReportPlay)
+			music.GET("/playlists/:puzzle_id", musicHandler.GetPlaylist)
+		}
+
+		// Admin routes - Full puzzle solutions for authoring
+		admin := api.Group("/admin")
+		admin.Use(middleware.AdminMiddleware(adminEmails))
+		{
+			admin.GET("/puzzles/:id", adminHandler.GetPuzzleByID)
+			admin.POST("/purchases/:id/refund", adminHandler.RefundPurchase)
+			admin.GET("/attempts/:id/score", adminHandler.GetAttemptScore)
+			admin.POST("/music/tracks", musicHandler.CreateTrack)
+			admin.PUT("/music/tracks/:id", musicHandler.UpdateTrack)
+			admin.DELETE("/music/tracks/:id", musicHandler.DeleteTrack)
+			admin.POST("/achievements", achievementHandler.CreateAchievement)
 		}
 	}
 
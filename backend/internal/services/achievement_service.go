@@ -0,0 +1,184 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"hh_puzzle/internal/models"
+	"hh_puzzle/internal/repository"
+)
+
+// AchievementInput is what a completed attempt tells the rule evaluator
+type AchievementInput struct {
+	UserID      uint
+	Puzzle      *models.Puzzle
+	HintsUsed   int
+	IsSolved    bool
+	TotalPoints int
+	Streak      int
+}
+
+// AchievementView is an achievement as listed to a user
+type AchievementView struct {
+	models.Achievement
+	IsEarned  bool       `json:"is_earned"`
+	AwardedAt *time.Time `json:"awarded_at,omitempty"`
+}
+
+// AchievementService evaluates achievement rules and lists badges
+type AchievementService interface {
+	WithTx(tx *repository.Tx) AchievementService
+	EvaluateAwards(input AchievementInput) ([]models.Achievement, error)
+	GetUserAchievements(userID uint) ([]AchievementView, error)
+	CreateAchievement(achievement *models.Achievement) error
+}
+
+type achievementService struct {
+	achievementRepo repository.AchievementRepository
+}
+
+// NewAchievementService creates a new achievement service
+func NewAchievementService(achievementRepo repository.AchievementRepository) AchievementService {
+	return &achievementService{
+		achievementRepo: achievementRepo,
+	}
+}
+
+// WithTx returns a copy of the service whose repositories run inside the transaction
+func (s *achievementService) WithTx(tx *repository.Tx) AchievementService {
+	return &achievementService{
+		achievementRepo: s.achievementRepo.WithTx(tx),
+	}
+}
+
+// EvaluateAwards checks every achievement the user has not earned against
+// their progress and returns the ones awarded by this call
+func (s *achievementService) EvaluateAwards(input AchievementInput) ([]models.Achievement, error) {
+	candidates, err := s.achievementRepo.FindUnawarded(input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var puzzleID *uint
+	if input.Puzzle != nil {
+		puzzleID = &input.Puzzle.ID
+	}
+
+	now := time.Now()
+	awarded := []models.Achievement{}
+	for _, achievement := range candidates {
+		earned, err := s.isEarned(achievement, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate achievement %s: %w", achievement.Code, err)
+		}
+		if !earned {
+			continue
+		}
+
+		created, err := s.achievementRepo.Award(input.UserID, achievement.ID, puzzleID, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to award achievement: %w", err)
+		}
+		// Another request may have awarded it first
+		if created {
+			awarded = append(awarded, achievement)
+		}
+	}
+
+	return awarded, nil
+}
+
+// isEarned applies an achievement's rule. Unknown rule types never match, so
+// a badge added for a newer server version is simply not awarded yet.
+func (s *achievementService) isEarned(achievement models.Achievement, input AchievementInput) (bool, error) {
+	criteria := achievement.Criteria
+
+	switch achievement.RuleType {
+	case models.RulePuzzlesCompleted:
+		completed, err := s.achievementRepo.CountCompletedPuzzles(input.UserID, criteria)
+		return completed >= int64(max(criteria.Count, 1)), err
+
+	case models.RuleNoHintSolve:
+		return input.IsSolved && input.HintsUsed == 0 && puzzleMatches(input.Puzzle, criteria), nil
+
+	case models.RuleCollection:
+		total, err := s.achievementRepo.CountPuzzles(criteria)
+		if err != nil || total == 0 {
+			return false, err
+		}
+		completed, err := s.achievementRepo.CountCompletedPuzzles(input.UserID, criteria)
+		return completed >= total, err
+
+	case models.RuleStreak:
+		return criteria.Days > 0 && input.Streak >= criteria.Days, nil
+
+	case models.RuleTotalPoints:
+		return criteria.Points > 0 && input.TotalPoints >= criteria.Points, nil
+	}
+
+	return false, nil
+}
+
+func (s *achievementService) GetUserAchievements(userID uint) ([]AchievementView, error) {
+	achievements, err := s.achievementRepo.FindActive()
+	if err != nil {
+		return nil, err
+	}
+
+	awards, err := s.achievementRepo.FindAwardedByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	awardedAt := make(map[uint]time.Time, len(awards))
+	for _, award := range awards {
+		awardedAt[award.AchievementID] = award.AwardedAt
+	}
+
+	views := make([]AchievementView, len(achievements))
+	for i, achievement := range achievements {
+		views[i] = AchievementView{Achievement: achievement}
+		if at, ok := awardedAt[achievement.ID]; ok {
+			views[i].IsEarned = true
+			views[i].AwardedAt = &at
+		}
+	}
+	return views, nil
+}
+
+// CreateAchievement adds a badge definition after checking its rule
+func (s *achievementService) CreateAchievement(achievement *models.Achievement) error {
+	if achievement.Code == "" || achievement.Name == "" {
+		return errors.New("code and name are required")
+	}
+
+	criteria := achievement.Criteria
+	switch achievement.RuleType {
+	case models.RulePuzzlesCompleted, models.RuleNoHintSolve, models.RuleCollection:
+	case models.RuleStreak:
+		if criteria.Days <= 0 {
+			return errors.New("streak rules need a positive days value")
+		}
+	case models.RuleTotalPoints:
+		if criteria.Points <= 0 {
+			return errors.New("total points rules need a positive points value")
+		}
+	default:
+		return fmt.Errorf("unknown rule type %q", achievement.RuleType)
+	}
+
+	achievement.ID = 0
+	achievement.IsActive = true
+	return s.achievementRepo.Create(achievement)
+}
+
+// puzzleMatches reports whether a puzzle passes the criteria filters
+func puzzleMatches(puzzle *models.Puzzle, criteria models.AchievementCriteria) bool {
+	if puzzle == nil {
+		return false
+	}
+	return (criteria.Difficulty == "" || puzzle.Difficulty == criteria.Difficulty) &&
+		(criteria.Decade == "" || puzzle.Decade == criteria.Decade) &&
+		(criteria.Region == "" || puzzle.Region == criteria.Region) &&
+		(criteria.Subgenre == "" || puzzle.Subgenre == criteria.Subgenre)
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"hh_puzzle/internal/models"
+)
+
+// Word statuses reported after an attempt is checked
+const (
+	WordStatusCorrect   = "correct"
+	WordStatusIncorrect = "incorrect"
+	WordStatusBlank     = "blank"
+)
+
+// WordResult reports the correctness of a single clue's answer
+type WordResult struct {
+	Number       int    `json:"number"`
+	Direction    string `json:"direction"` // across, down
+	Status       string `json:"status"`    // correct, incorrect, blank
+	CorrectCells int    `json:"correct_cells"`
+	Length       int    `json:"length"`
+}
+
+// AnswerCheck contains the outcome of comparing grid entries with the solution
+type AnswerCheck struct {
+	Words        []WordResult    `json:"words"`
+	CellResults  map[string]bool `json:"cell_results"` // keyed by "x,y", true when correct
+	CorrectCells int             `json:"correct_cells"`
+	TotalCells   int             `json:"total_cells"`
+	Accuracy     float64         `json:"accuracy"`
+}
+
+// cellKey builds the "x,y" key used for grid entries
+func cellKey(x, y int) string {
+	return fmt.Sprintf("%d,%d", x, y)
+}
+
+// clueCells returns the grid keys covered by a clue's answer
+func clueCells(clue models.Clue) []string {
+	keys := make([]string, len(clue.Answer))
+	for i := range clue.Answer {
+		keys[i] = cellKey(clue.Cell(i))
+	}
+	return keys
+}
+
+// entriesFromState extracts "x,y" -> letter entries from a stored attempt state
+func entriesFromState(state models.JSONB) map[string]string {
+	entries := make(map[string]string, len(state))
+	for key, value := range state {
+		if letter, ok := value.(string); ok {
+			entries[key] = letter
+		}
+	}
+	return entries
+}
+
+// entriesToState converts grid entries into the JSONB stored on an attempt
+func entriesToState(entries map[string]string) models.JSONB {
+	state := make(models.JSONB, len(entries))
+	for key, letter := range entries {
+		if letter = normalizeEntry(letter); letter != "" {
+			state[key] = letter
+		}
+	}
+	return state
+}
+
+// normalizeEntry uppercases a player entry and drops surrounding whitespace
+func normalizeEntry(entry string) string {
+	return strings.ToUpper(strings.TrimSpace(entry))
+}
+
+// checkAnswers compares the player's entries cell by cell against the puzzle solution
+func checkAnswers(puzzle *models.Puzzle, entries map[string]string) *AnswerCheck {
+	check := &AnswerCheck{
+		CellResults: make(map[string]bool),
+	}
+
+	for _, clue := range puzzle.Clues() {
+		result := WordResult{
+			Number:    clue.Number,
+			Direction: clue.Direction,
+			Length:    len(clue.Answer),
+		}
+
+		filled := 0
+		for i, key := range clueCells(clue) {
+			entry := normalizeEntry(entries[key])
+			correct := entry == string(clue.Answer[i])
+			if entry != "" {
+				filled++
+			}
+			if correct {
+				result.CorrectCells++
+			}
+
+			// Crossing cells belong to two words but are only counted once
+			if _, seen := check.CellResults[key]; !seen {
+				check.CellResults[key] = correct
+				check.TotalCells++
+				if correct {
+					check.CorrectCells++
+				}
+			}
+		}
+
+		switch {
+		case result.CorrectCells == result.Length:
+			result.Status = WordStatusCorrect
+		case filled == 0:
+			result.Status = WordStatusBlank
+		default:
+			result.Status = WordStatusIncorrect
+		}
+
+		check.Words = append(check.Words, result)
+	}
+
+	if check.TotalCells > 0 {
+		check.Accuracy = float64(check.CorrectCells) / float64(check.TotalCells) * 100
+	}
+
+	return check
+}
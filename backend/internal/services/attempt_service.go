@@ -16,65 +16,176 @@ type AttemptResult struct {
 	AccuracyPercentage float64 `json:"accuracy_percentage"`
 	TimeBonus          int     `json:"time_bonus"`
 	NewStreak          int     `json:"new_streak"`
+
+	// Active play time measured by the server, and whether the time the app
+	// reported disagreed with it
+	CompletionTime models.Seconds `json:"completion_seconds"`
+	TimeMismatch   bool           `json:"time_mismatch"`
+
+	// Run of the puzzle; replays earn no points
+	RunNumber int             `json:"run_number"`
+	IsReplay  bool            `json:"is_replay"`
+	BestTime  *models.Seconds `json:"best_time_seconds,omitempty"`
+	IsNewBest bool            `json:"is_new_best"`
+
+	// Members of a shared attempt with their share of the points
+	Members []models.AttemptMember `json:"members,omitempty"`
+
+	// Itemised points under the scoring policy the attempt was scored with
+	ScoreBreakdown *models.ScoreBreakdown `json:"score_breakdown"`
+
+	// Streak freezes spent on missed days and earned by this submission
+	StreakFreezesUsed   int `json:"streak_freezes_used"`
+	StreakFreezesEarned int `json:"streak_freezes_earned"`
+
+	// Answer checking
+	IsSolved     bool            `json:"is_solved"`
+	CorrectCells int             `json:"correct_cells"`
+	TotalCells   int             `json:"total_cells"`
+	Words        []WordResult    `json:"words"`
+	Cells        map[string]bool `json:"cells"` // keyed by "x,y", true when correct
+
+	// Facts unlocked and achievements awarded by this submission
+	UnlockedFacts []models.HipHopFact  `json:"unlocked_facts"`
+	Achievements  []models.Achievement `json:"achievements"`
+}
+
+// HintResult contains the outcome of a hint request
+type HintResult struct {
+	HintType   string            `json:"hint_type"`
+	ClueNumber int               `json:"clue_number"`
+	Direction  string            `json:"direction"`
+	Revealed   map[string]string `json:"revealed,omitempty"` // "x,y" -> letter, for reveal hints
+	Checked    map[string]bool   `json:"checked,omitempty"`  // "x,y" -> correct, for check hints
+	HintsUsed  int               `json:"hints_used"`
+	Version    int               `json:"version"` // attempt state version after the hint
 }
 
 // AttemptService handles puzzle attempt business logic
 type AttemptService interface {
-	StartAttempt(userID, puzzleID uint) (*models.PuzzleAttempt, error)
-	UpdateProgress(attemptID uint, currentState map[string]interface{}) error
-	SubmitAttempt(attemptID uint, completionTime, hintsUsed int) (*AttemptResult, error)
+	StartAttempt(userID, puzzleID uint, replay bool) (*models.PuzzleAttempt, error)
+	UpdateProgress(userID, attemptID uint, currentState map[string]interface{}) (*ProgressDelta, error)
+	SyncProgress(userID, attemptID uint, baseVersion int, cells map[string]string, rejectStale bool) (*ProgressDelta, error)
+	GetProgress(userID, attemptID uint, since int) (*ProgressDelta, error)
+	UseHint(userID, attemptID uint, hintType string, clueNumber int, direction string) (*HintResult, error)
+	PauseAttempt(userID, attemptID uint) (*AttemptTimer, error)
+	ResumeAttempt(userID, attemptID uint) (*AttemptTimer, error)
+	GetAttemptTimer(userID, attemptID uint) (*AttemptTimer, error)
+	SubmitAttempt(userID, attemptID uint, entries map[string]string, clientTime models.Seconds) (*AttemptResult, error)
 	GetUserAttempts(userID uint) ([]models.PuzzleAttempt, error)
-	GetAttemptByID(attemptID uint) (*models.PuzzleAttempt, error)
+	GetBestTimes(userID uint) ([]models.PuzzleBest, error)
+	GetAttemptByID(userID, attemptID uint) (*models.PuzzleAttempt, error)
+	ExplainScore(attemptID uint, policyVersion string) (*ScoreExplanation, error)
+}
+
+// ScoreExplanation compares an attempt's recorded score with the score its
+// recorded inputs get under a policy version
+type ScoreExplanation struct {
+	AttemptID    uint                   `json:"attempt_id"`
+	PointsEarned int                    `json:"points_earned"`
+	Recorded     *models.ScoreBreakdown `json:"recorded"`
+	Recalculated *models.ScoreBreakdown `json:"recalculated"`
 }
 
 type attemptService struct {
+	txManager   repository.TxManager
 	attemptRepo repository.AttemptRepository
 	userRepo    repository.UserRepository
 	puzzleRepo  repository.PuzzleRepository
+	hintRepo    repository.HintRepository
+	pauseRepo   repository.PauseRepository
+	bestRepo    repository.PuzzleBestRepository
+	memberRepo  repository.AttemptMemberRepository
+
+	entitlementService EntitlementService
+	streakService      StreakService
+	leaderboardService LeaderboardService
+	factService        FactService
+	achievementService AchievementService
+	scoringService     ScoringService
 }
 
 // NewAttemptService creates a new attempt service
 func NewAttemptService(
+	txManager repository.TxManager,
 	attemptRepo repository.AttemptRepository,
 	userRepo repository.UserRepository,
 	puzzleRepo repository.PuzzleRepository,
+	hintRepo repository.HintRepository,
+	pauseRepo repository.PauseRepository,
+	bestRepo repository.PuzzleBestRepository,
+	memberRepo repository.AttemptMemberRepository,
+	entitlementService EntitlementService,
+	streakService StreakService,
+	leaderboardService LeaderboardService,
+	factService FactService,
+	achievementService AchievementService,
+	scoringService ScoringService,
 ) AttemptService {
 	return &attemptService{
+		txManager:   txManager,
 		attemptRepo: attemptRepo,
 		userRepo:    userRepo,
 		puzzleRepo:  puzzleRepo,
+		hintRepo:    hintRepo,
+		pauseRepo:   pauseRepo,
+		bestRepo:    bestRepo,
+		memberRepo:  memberRepo,
+
+		entitlementService: entitlementService,
+		streakService:      streakService,
+		leaderboardService: leaderboardService,
+		factService:        factService,
+		achievementService: achievementService,
+		scoringService:     scoringService,
 	}
 }
 
-func (s *attemptService) StartAttempt(userID, puzzleID uint) (*models.PuzzleAttempt, error) {
+// StartAttempt returns the user's unfinished run of the puzzle or starts the
+// first one. Once the puzzle is completed a new run is only started as a
+// replay.
+func (s *attemptService) StartAttempt(userID, puzzleID uint, replay bool) (*models.PuzzleAttempt, error) {
 	// Check if puzzle exists
 	puzzle, err := s.puzzleRepo.FindByID(puzzleID)
 	if err != nil {
 		return nil, errors.New("puzzle not found")
 	}
 
-	// Check if attempt already exists
-	existingAttempt, err := s.attemptRepo.FindByUserAndPuzzle(userID, puzzleID)
-	
+	allowed, err := s.entitlementService.CanPlayPuzzle(userID, puzzle)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrPuzzleLocked
+	}
+
+	// Check if a run already exists
+	existingAttempt, err := s.attemptRepo.FindLatestRun(userID, puzzleID)
+
 	// If error is NOT "attempt not found", return the error
 	if err != nil && err.Error() != "attempt not found" {
 		return nil, err
 	}
 
-	// If attempt exists and not completed, return it
+	// If the latest run is not completed, continue it
 	if existingAttempt != nil && !existingAttempt.IsCompleted {
 		return existingAttempt, nil
 	}
 
-	// If attempt exists and completed, don't allow restart
-	if existingAttempt != nil && existingAttempt.IsCompleted {
-		return nil, errors.New("puzzle already completed")
+	// A completed puzzle can only be played again as a replay
+	runNumber := 1
+	if existingAttempt != nil {
+		if !replay {
+			return nil, ErrPuzzleCompleted
+		}
+		runNumber = existingAttempt.RunNumber + 1
 	}
 
-	// Create new attempt (existingAttempt is nil, so no attempt exists yet)
+	// Create new attempt
 	attempt := &models.PuzzleAttempt{
 		UserID:       userID,
 		PuzzleID:     puzzleID,
+		RunNumber:    runNumber,
 		CurrentState: models.JSONB{},
 		IsCompleted:  false,
 		HintsUsed:    0,
@@ -92,29 +203,294 @@ func (s *attemptService) StartAttempt(userID, puzzleID uint) (*models.PuzzleAtte
 	return attempt, nil
 }
 
-func (s *attemptService) UpdateProgress(attemptID uint, currentState map[string]interface{}) error {
-	attempt, err := s.attemptRepo.FindByID(attemptID)
+// UpdateProgress replaces the attempt's whole grid and returns the full state
+func (s *attemptService) UpdateProgress(userID, attemptID uint, currentState map[string]interface{}) (*ProgressDelta, error) {
+	return s.writeProgress(userID, attemptID, 0, false, func(attempt *models.PuzzleAttempt) map[string]string {
+		return stateChanges(attempt.CurrentState, currentState)
+	})
+}
+
+// SyncProgress applies cell changes made on top of baseVersion and returns
+// every change since baseVersion, including those made by other devices. A
+// stale write is rejected with ErrProgressConflict and the server's changes
+// when rejectStale is set; otherwise it is merged cell by cell and the last
+// write to a cell wins.
+func (s *attemptService) SyncProgress(userID, attemptID uint, baseVersion int, cells map[string]string, rejectStale bool) (*ProgressDelta, error) {
+	return s.writeProgress(userID, attemptID, baseVersion, rejectStale, func(attempt *models.PuzzleAttempt) map[string]string {
+		return cells
+	})
+}
+
+// GetProgress returns the cells changed after a version
+func (s *attemptService) GetProgress(userID, attemptID uint, since int) (*ProgressDelta, error) {
+	attempt, err := s.getOwnedAttempt(userID, attemptID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	return progressSince(attempt, since), nil
+}
+
+// writeProgress applies the cells built by cellsFor to the attempt's state with
+// optimistic locking. When another write lands first the attempt is reloaded
+// and the cells applied again on top of it.
+func (s *attemptService) writeProgress(
+	userID, attemptID uint,
+	baseVersion int,
+	rejectStale bool,
+	cellsFor func(attempt *models.PuzzleAttempt) map[string]string,
+) (*ProgressDelta, error) {
+	for i := 0; i < maxProgressWrites; i++ {
+		attempt, err := s.getOwnedAttempt(userID, attemptID)
+		if err != nil {
+			return nil, err
+		}
+
+		if attempt.IsCompleted {
+			return nil, ErrAttemptCompleted
+		}
+		if err := s.ensureNotPaused(attempt); err != nil {
+			return nil, err
+		}
+		if rejectStale && attempt.Version != baseVersion {
+			return progressSince(attempt, baseVersion), ErrProgressConflict
+		}
+
+		cells := cellsFor(attempt)
+		if err := validateCells(&attempt.Puzzle, cells); err != nil {
+			return nil, err
+		}
+
+		fromVersion := attempt.Version
+		if setCells(attempt, cells, userID) == 0 {
+			return progressSince(attempt, baseVersion), nil
+		}
+
+		updated, err := s.attemptRepo.UpdateInProgress(attempt, fromVersion, "current_state")
+		if err != nil {
+			return nil, err
+		}
+		if updated {
+			return progressSince(attempt, baseVersion), nil
+		}
+	}
+
+	return nil, ErrProgressConflict
+}
+
+func (s *attemptService) UseHint(userID, attemptID uint, hintType string, clueNumber int, direction string) (*HintResult, error) {
+	attempt, err := s.getOwnedAttempt(userID, attemptID)
+	if err != nil {
+		return nil, err
+	}
+
+	if attempt.IsCompleted {
+		return nil, ErrAttemptCompleted
+	}
+	if err := s.ensureNotPaused(attempt); err != nil {
+		return nil, err
+	}
+
+	// Work out the answer from the puzzle's clues
+	clue, ok := attempt.Puzzle.FindClue(clueNumber, direction)
+	if !ok {
+		return nil, errors.New("clue not found")
+	}
+
+	state := attempt.CurrentState
+	if state == nil {
+		state = models.JSONB{}
+	}
+
+	result := &HintResult{
+		HintType:   hintType,
+		ClueNumber: clue.Number,
+		Direction:  clue.Direction,
+	}
+	hintCells := models.JSONB{}
+
+	switch hintType {
+	case models.HintRevealLetter, models.HintRevealWord:
+		result.Revealed = make(map[string]string)
+		for i, key := range clueCells(*clue) {
+			letter := string(clue.Answer[i])
+			entry, _ := state[key].(string)
+			if normalizeEntry(entry) == letter {
+				continue
+			}
+
+			result.Revealed[key] = letter
+			hintCells[key] = letter
+
+			// A letter reveal only fills the first wrong or empty cell
+			if hintType == models.HintRevealLetter {
+				break
+			}
+		}
+		if len(result.Revealed) == 0 {
+			return nil, errors.New("word is already correct")
+		}
+
+	case models.HintCheckWord:
+		result.Checked = make(map[string]bool)
+		for i, key := range clueCells(*clue) {
+			entry, _ := state[key].(string)
+			if entry = normalizeEntry(entry); entry == "" {
+				continue
+			}
+			correct := entry == string(clue.Answer[i])
+			result.Checked[key] = correct
+			hintCells[key] = correct
+		}
+
+	default:
+		return nil, errors.New("hint type must be 'reveal_letter', 'reveal_word', or 'check_word'")
+	}
+
+	// Log the hint
+	hint := &models.AttemptHint{
+		AttemptID:  attempt.ID,
+		HintType:   hintType,
+		ClueNumber: clue.Number,
+		Direction:  clue.Direction,
+		Cells:      hintCells,
+	}
+	// Write revealed cells into the attempt state
+	fromVersion := attempt.Version
+	setCells(attempt, result.Revealed, 0)
+	attempt.HintsUsed++
+
+	err = s.txManager.Transaction(func(tx *repository.Tx) error {
+		if err := s.hintRepo.WithTx(tx).Create(hint); err != nil {
+			return fmt.Errorf("failed to record hint: %w", err)
+		}
+
+		updated, err := s.attemptRepo.WithTx(tx).UpdateInProgress(attempt, fromVersion, "current_state", "hints_used")
+		if err != nil {
+			return fmt.Errorf("failed to update attempt: %w", err)
+		}
+		if !updated {
+			return s.staleWriteError(attempt.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.HintsUsed = attempt.HintsUsed
+	result.Version = attempt.Version
+	return result, nil
+}
+
+// PauseAttempt stops the attempt's timer. Pausing a paused attempt leaves the
+// open pause as it is.
+func (s *attemptService) PauseAttempt(userID, attemptID uint) (*AttemptTimer, error) {
+	attempt, err := s.getOwnedAttempt(userID, attemptID)
+	if err != nil {
+		return nil, err
 	}
 
 	if attempt.IsCompleted {
-		return errors.New("cannot update completed attempt")
+		return nil, ErrAttemptCompleted
+	}
+
+	pause := &models.AttemptPause{
+		AttemptID: attempt.ID,
+		PausedAt:  time.Now(),
+	}
+	if _, err := s.pauseRepo.Open(pause); err != nil {
+		return nil, fmt.Errorf("failed to pause attempt: %w", err)
+	}
+
+	return s.timerOf(attempt)
+}
+
+// ResumeAttempt restarts the timer of a paused attempt
+func (s *attemptService) ResumeAttempt(userID, attemptID uint) (*AttemptTimer, error) {
+	attempt, err := s.getOwnedAttempt(userID, attemptID)
+	if err != nil {
+		return nil, err
+	}
+
+	if attempt.IsCompleted {
+		return nil, ErrAttemptCompleted
+	}
+
+	resumed, err := s.pauseRepo.CloseOpen(attempt.ID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resume attempt: %w", err)
+	}
+	if !resumed {
+		return nil, ErrAttemptNotPaused
+	}
+
+	return s.timerOf(attempt)
+}
+
+// GetAttemptTimer returns how long the attempt has been played so far
+func (s *attemptService) GetAttemptTimer(userID, attemptID uint) (*AttemptTimer, error) {
+	attempt, err := s.getOwnedAttempt(userID, attemptID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.timerOf(attempt)
+}
+
+// ensureNotPaused rejects play on an attempt whose clock is stopped
+func (s *attemptService) ensureNotPaused(attempt *models.PuzzleAttempt) error {
+	paused, err := s.pauseRepo.IsPaused(attempt.ID)
+	if err != nil {
+		return err
+	}
+	if paused {
+		return ErrAttemptPaused
+	}
+	return nil
+}
+
+// timerOf builds the attempt's timer, stopped at completion for completed attempts
+func (s *attemptService) timerOf(attempt *models.PuzzleAttempt) (*AttemptTimer, error) {
+	pauses, err := s.pauseRepo.FindByAttempt(attempt.ID)
+	if err != nil {
+		return nil, err
 	}
 
-	attempt.CurrentState = currentState
-	return s.attemptRepo.Update(attempt)
+	now := time.Now()
+	if attempt.CompletedAt != nil {
+		now = *attempt.CompletedAt
+	}
+	return newAttemptTimer(attempt, pauses, now), nil
 }
 
-func (s *attemptService) SubmitAttempt(attemptID uint, completionTime, hintsUsed int) (*AttemptResult, error) {
+// SubmitAttempt scores an attempt and applies the result to the profile,
+// streak, leaderboard, facts and achievements in one transaction. The attempt
+// is only completed if it still is not, so a double submit awards points once.
+// The completion time is the active time measured by the server; clientTime
+// is only compared with it, and a large difference flags the attempt. A
+// paused attempt is rejected with ErrAttemptPaused until it is resumed.
+func (s *attemptService) SubmitAttempt(userID, attemptID uint, entries map[string]string, clientTime models.Seconds) (*AttemptResult, error) {
 	// Get attempt
-	attempt, err := s.attemptRepo.FindByID(attemptID)
+	attempt, err := s.getOwnedAttempt(userID, attemptID)
 	if err != nil {
 		return nil, err
 	}
 
 	if attempt.IsCompleted {
-		return nil, errors.New("attempt already completed")
+		return nil, ErrAttemptCompleted
+	}
+
+	// Time the attempt from its start, leaving out pauses. A paused attempt
+	// must be resumed before it is submitted.
+	now := time.Now()
+	pauses, err := s.pauseRepo.FindByAttempt(attempt.ID)
+	if err != nil {
+		return nil, err
+	}
+	completionTime, err := SubmittedTime(attempt.StartedAt, pauses, now)
+	if err != nil {
+		return nil, err
 	}
 
 	// Get puzzle
@@ -123,126 +499,324 @@ func (s *attemptService) SubmitAttempt(attemptID uint, completionTime, hintsUsed
 		return nil, err
 	}
 
-	// Calculate accuracy (simplified - in real app, would check actual answers)
-	accuracy := 100.0 - float64(hintsUsed*5) // Each hint reduces accuracy by 5%
-	if accuracy < 0 {
-		accuracy = 0
+	// Fall back to the saved progress when no entries are submitted
+	if len(entries) == 0 {
+		entries = entriesFromState(attempt.CurrentState)
 	}
 
-	// Calculate points
-	basePoints := puzzle.BasePoints
-	timeBonus := s.calculateTimeBonus(completionTime, puzzle.EstimatedTime)
-	hintsPenalty := hintsUsed * 10
-	accuracyBonus := int(accuracy * 0.5)
+	// Check the entries against the solution
+	check := checkAnswers(puzzle, entries)
 
-	totalPoints := basePoints + timeBonus - hintsPenalty + accuracyBonus
-	if totalPoints < 0 {
-		totalPoints = 0
+	// Hints are counted from the server's hint log, not the client
+	hintCount, err := s.hintRepo.CountByAttempt(attempt.ID)
+	if err != nil {
+		return nil, err
 	}
+	hintsUsed := int(hintCount)
+	accuracy := check.Accuracy
+	isSolved := check.TotalCells > 0 && check.CorrectCells == check.TotalCells
+
+	timeMismatch := clientTime > 0 && timesDisagree(completionTime, clientTime)
 
 	// Update attempt
-	now := time.Now()
 	attempt.IsCompleted = true
 	attempt.CompletedAt = &now
+	setCells(attempt, stateChanges(attempt.CurrentState, entriesToState(entries)), userID)
 	attempt.CompletionTime = &completionTime
+	attempt.TimeMismatch = timeMismatch
+	if clientTime > 0 {
+		attempt.ClientCompletionTime = &clientTime
+	}
 	attempt.HintsUsed = hintsUsed
-	attempt.PointsEarned = totalPoints
 	attempt.AccuracyPercentage = &accuracy
 
-	if err := s.attemptRepo.Update(attempt); err != nil {
-		return nil, fmt.Errorf("failed to update attempt: %w", err)
+	result := &AttemptResult{
+		IsCompleted:        true,
+		CompletionTime:     completionTime,
+		TimeMismatch:       timeMismatch,
+		RunNumber:          attempt.RunNumber,
+		IsReplay:           attempt.IsReplay(),
+		AccuracyPercentage: accuracy,
+		IsSolved:           isSolved,
+		CorrectCells:       check.CorrectCells,
+		TotalCells:         check.TotalCells,
+		Words:              check.Words,
+		Cells:              check.CellResults,
+	}
+
+	scoreInput := ScoreInput{
+		Puzzle:         puzzle,
+		Accuracy:       accuracy,
+		CompletionTime: completionTime,
+		HintsUsed:      hintsUsed,
 	}
 
-	// Update user profile
-	user, err := s.userRepo.GetWithProfile(attempt.UserID)
+	err = s.txManager.Transaction(func(tx *repository.Tx) error {
+		// Replays are scored to explain the run but leave the profile,
+		// streak and leaderboards alone
+		if attempt.IsReplay() {
+			breakdown := s.scoringService.Score(scoreInput)
+			applyScore(attempt, result, &breakdown)
+			if err := s.completeAttempt(tx, attempt, now); err != nil {
+				return err
+			}
+			return s.recordRun(tx, attempt, isSolved, result)
+		}
+
+		// Lock the profile so submits of other attempts wait for this one
+		userRepo := s.userRepo.WithTx(tx)
+		profile, err := userRepo.FindProfileForUpdate(attempt.UserID)
+		if err != nil {
+			return err
+		}
+
+		// Update streak and last puzzle date on the player's local calendar
+		streak, err := s.streakService.WithTx(tx).RecordCompletion(attempt.UserID, profile, now)
+		if err != nil {
+			return err
+		}
+		result.NewStreak = streak.Streak
+		result.StreakFreezesUsed = len(streak.FrozenDays)
+		result.StreakFreezesEarned = streak.FreezesEarned
+
+		// Score with the puzzle's policy; streak multipliers see the new streak
+		scoreInput.Streak = streak.Streak
+		breakdown := s.scoringService.Score(scoreInput)
+		applyScore(attempt, result, &breakdown)
+		totalPoints := attempt.PointsEarned
+
+		if err := s.completeAttempt(tx, attempt, now); err != nil {
+			return err
+		}
+
+		// The members of a shared attempt split its points; the owner keeps
+		// only their share
+		if attempt.IsShared {
+			result.Members, err = s.memberRepo.WithTx(tx).FindByAttempt(attempt.ID)
+			if err != nil {
+				return err
+			}
+			splitPoints(totalPoints, result.Members, attempt.CellAuthors, check.CellResults)
+			if totalPoints, err = s.creditMembers(tx, attempt, result.Members, completionTime, now); err != nil {
+				return err
+			}
+		}
+
+		profile.TotalPoints += totalPoints
+		profile.PuzzlesCompleted++
+		if err := userRepo.UpdateProfile(profile); err != nil {
+			return fmt.Errorf("failed to update user profile: %w", err)
+		}
+
+		// Add the completion to this week's leaderboard
+		if err := s.leaderboardService.WithTx(tx).RecordCompletion(attempt.UserID, totalPoints, completionTime, now); err != nil {
+			return fmt.Errorf("failed to update leaderboard: %w", err)
+		}
+
+		// Unlock facts earned with the new totals
+		result.UnlockedFacts, err = s.factService.WithTx(tx).EvaluateUnlocks(
+			attempt.UserID,
+			attempt.PuzzleID,
+			profile.TotalPoints,
+			profile.CurrentStreak,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to unlock facts: %w", err)
+		}
+
+		// Award achievements whose rules are now met
+		result.Achievements, err = s.achievementService.WithTx(tx).EvaluateAwards(AchievementInput{
+			UserID:      attempt.UserID,
+			Puzzle:      puzzle,
+			HintsUsed:   hintsUsed,
+			IsSolved:    isSolved,
+			TotalPoints: profile.TotalPoints,
+			Streak:      profile.CurrentStreak,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to award achievements: %w", err)
+		}
+
+		return s.recordRun(tx, attempt, isSolved, result)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if user.Profile != nil {
-		// Add points
-		user.Profile.TotalPoints += totalPoints
+	return result, nil
+}
 
-		// Increment puzzles completed
-		user.Profile.PuzzlesCompleted++
+// applyScore records a score breakdown on the attempt and its result.
+// Replays keep the breakdown but earn no points.
+func applyScore(attempt *models.PuzzleAttempt, result *AttemptResult, breakdown *models.ScoreBreakdown) {
+	points := breakdown.Total
+	if attempt.IsReplay() {
+		points = 0
+	}
 
-		// Update streak
-		newStreak := s.updateStreak(user.Profile)
+	attempt.PointsEarned = points
+	attempt.ScoringPolicyVersion = breakdown.PolicyVersion
+	attempt.ScoreBreakdown = breakdown
+	result.PointsEarned = points
+	result.TimeBonus = breakdown.Points(models.ScoreRuleTimeBonus)
+	result.ScoreBreakdown = breakdown
+}
 
-		// Update last puzzle date
-		now := time.Now()
-		user.Profile.LastPuzzleDate = &now
+// creditMembers adds their share of a shared attempt's points and the
+// completion to the profile and weekly leaderboard of every member but the
+// owner, whose share it returns.
+// Streaks, facts and achievements stay with the owner's attempt.
+func (s *attemptService) creditMembers(
+	tx *repository.Tx,
+	attempt *models.PuzzleAttempt,
+	members []models.AttemptMember,
+	completionTime models.Seconds,
+	now time.Time,
+) (int, error) {
+	userRepo := s.userRepo.WithTx(tx)
+	memberRepo := s.memberRepo.WithTx(tx)
+	leaderboardService := s.leaderboardService.WithTx(tx)
+
+	ownerPoints := 0
+	for i := range members {
+		member := &members[i]
+		if err := memberRepo.UpdateShare(member); err != nil {
+			return 0, fmt.Errorf("failed to record member points: %w", err)
+		}
+		if member.UserID == attempt.UserID {
+			ownerPoints = member.PointsEarned
+			continue
+		}
 
-		if err := s.userRepo.Update(user); err != nil {
-			return nil, fmt.Errorf("failed to update user profile: %w", err)
+		profile, err := userRepo.FindProfileForUpdate(member.UserID)
+		if err != nil {
+			return 0, err
+		}
+		profile.TotalPoints += member.PointsEarned
+		profile.PuzzlesCompleted++
+		if err := userRepo.UpdateProfile(profile); err != nil {
+			return 0, fmt.Errorf("failed to update user profile: %w", err)
 		}
 
-		result := &AttemptResult{
-			IsCompleted:        true,
-			PointsEarned:       totalPoints,
-			AccuracyPercentage: accuracy,
-			TimeBonus:          timeBonus,
-			NewStreak:          newStreak,
+		if err := leaderboardService.RecordCompletion(member.UserID, member.PointsEarned, completionTime, now); err != nil {
+			return 0, fmt.Errorf("failed to update leaderboard: %w", err)
 		}
+	}
+	return ownerPoints, nil
+}
+
+// completeAttempt saves the submitted attempt and ends any open pause. Only
+// the first of concurrent submits gets to complete the attempt.
+func (s *attemptService) completeAttempt(tx *repository.Tx, attempt *models.PuzzleAttempt, now time.Time) error {
+	completed, err := s.attemptRepo.WithTx(tx).MarkCompleted(attempt)
+	if err != nil {
+		return fmt.Errorf("failed to update attempt: %w", err)
+	}
+	if !completed {
+		return ErrAttemptCompleted
+	}
+
+	// A pause opened while the submit was being scored ends with it
+	if _, err := s.pauseRepo.WithTx(tx).CloseOpen(attempt.ID, now); err != nil {
+		return fmt.Errorf("failed to close pause: %w", err)
+	}
+	return nil
+}
 
-		return result, nil
+// recordRun counts the completed run toward the user's best time on the puzzle
+func (s *attemptService) recordRun(tx *repository.Tx, attempt *models.PuzzleAttempt, isSolved bool, result *AttemptResult) error {
+	best, err := s.bestRepo.WithTx(tx).RecordRun(attempt, isSolved)
+	if err != nil {
+		return fmt.Errorf("failed to record best time: %w", err)
 	}
 
-	return nil, errors.New("user profile not found")
+	result.BestTime = best.BestTime
+	result.IsNewBest = best.BestAttemptID != nil && *best.BestAttemptID == attempt.ID
+	return nil
 }
 
 func (s *attemptService) GetUserAttempts(userID uint) ([]models.PuzzleAttempt, error) {
 	return s.attemptRepo.FindByUser(userID)
 }
 
-func (s *attemptService) GetAttemptByID(attemptID uint) (*models.PuzzleAttempt, error) {
-	return s.attemptRepo.FindByID(attemptID)
+// GetBestTimes returns the user's best times and run counts per puzzle
+func (s *attemptService) GetBestTimes(userID uint) ([]models.PuzzleBest, error) {
+	return s.bestRepo.FindByUser(userID)
+}
+
+func (s *attemptService) GetAttemptByID(userID, attemptID uint) (*models.PuzzleAttempt, error) {
+	return s.getOwnedAttempt(userID, attemptID)
 }
 
-// calculateTimeBonus calculates bonus points based on completion time
-func (s *attemptService) calculateTimeBonus(completionTime, estimatedTime int) int {
-	if estimatedTime == 0 {
-		estimatedTime = 300 // Default 5 minutes
+// ExplainScore returns the attempt's recorded score breakdown next to the
+// breakdown its recorded inputs get under policyVersion, which defaults to the
+// version the attempt was scored with
+func (s *attemptService) ExplainScore(attemptID uint, policyVersion string) (*ScoreExplanation, error) {
+	attempt, err := s.attemptRepo.FindByID(attemptID)
+	if err != nil {
+		if err.Error() == "attempt not found" {
+			return nil, ErrAttemptNotFound
+		}
+		return nil, err
+	}
+	if attempt.ScoreBreakdown == nil {
+		return nil, ErrScoreNotRecorded
 	}
 
-	// Bonus if completed faster than estimated time
-	if completionTime < estimatedTime {
-		percentFaster := float64(estimatedTime-completionTime) / float64(estimatedTime)
-		bonus := int(percentFaster * 50) // Up to 50 bonus points
-		return bonus
+	puzzle, err := s.puzzleRepo.FindByID(attempt.PuzzleID)
+	if err != nil {
+		return nil, err
 	}
 
-	return 0
-}
+	recalculated, err := s.scoringService.Recalculate(puzzle, attempt.ScoreBreakdown, policyVersion)
+	if err != nil {
+		return nil, err
+	}
 
-// updateStreak updates the user's puzzle completion streak
-func (s *attemptService) updateStreak(profile *models.UserProfile) int {
-	now := time.Now()
+	return &ScoreExplanation{
+		AttemptID:    attempt.ID,
+		PointsEarned: attempt.PointsEarned,
+		Recorded:     attempt.ScoreBreakdown,
+		Recalculated: recalculated,
+	}, nil
+}
 
-	if profile.LastPuzzleDate == nil {
-		// First puzzle
-		profile.CurrentStreak = 1
-		profile.LongestStreak = 1
-		return 1
+// staleWriteError explains why a conditional attempt update changed nothing
+func (s *attemptService) staleWriteError(attemptID uint) error {
+	attempt, err := s.attemptRepo.FindByID(attemptID)
+	if err != nil {
+		return err
 	}
+	if attempt.IsCompleted {
+		return ErrAttemptCompleted
+	}
+	return ErrProgressConflict
+}
 
-	lastDate := profile.LastPuzzleDate.Truncate(24 * time.Hour)
-	today := now.Truncate(24 * time.Hour)
-	yesterday := today.Add(-24 * time.Hour)
+// getOwnedAttempt loads an attempt and checks that it belongs to the user or,
+// for a shared attempt, that the user is one of its members. Every operation
+// on an existing attempt goes through here.
+func (s *attemptService) getOwnedAttempt(userID, attemptID uint) (*models.PuzzleAttempt, error) {
+	attempt, err := s.attemptRepo.FindByID(attemptID)
+	if err != nil {
+		if err.Error() == "attempt not found" {
+			return nil, ErrAttemptNotFound
+		}
+		return nil, err
+	}
 
-	if lastDate.Equal(yesterday) {
-		// Consecutive day - increment streak
-		profile.CurrentStreak++
-		if profile.CurrentStreak > profile.LongestStreak {
-			profile.LongestStreak = profile.CurrentStreak
+	if attempt.UserID != userID {
+		if !attempt.IsShared {
+			return nil, ErrAttemptForbidden
+		}
+		isMember, err := s.memberRepo.IsMember(attempt.ID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !isMember {
+			return nil, ErrAttemptForbidden
 		}
-	} else if lastDate.Equal(today) {
-		// Same day - maintain streak
-		// Do nothing
-	} else {
-		// Streak broken - reset to 1
-		profile.CurrentStreak = 1
 	}
 
-	return profile.CurrentStreak
+	return attempt, nil
 }
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"math"
+	"time"
+
+	"hh_puzzle/internal/models"
+)
+
+// A client-reported completion time may differ from the server's by this
+// much, or by this share of the server's time when that is larger, before the
+// attempt is flagged
+const (
+	timerToleranceMin  = 15 * time.Second
+	timerToleranceRate = 0.1
+)
+
+// AttemptTimer is the server's view of how long an attempt has been played
+type AttemptTimer struct {
+	AttemptID   uint           `json:"attempt_id"`
+	StartedAt   time.Time      `json:"started_at"`
+	IsPaused    bool           `json:"is_paused"`
+	PausedAt    *time.Time     `json:"paused_at,omitempty"`
+	ElapsedTime models.Seconds `json:"elapsed_seconds"` // active time, excluding pauses
+	PausedTime  models.Seconds `json:"paused_seconds"`
+}
+
+// newAttemptTimer builds the timer of an attempt from its pauses at now
+func newAttemptTimer(attempt *models.PuzzleAttempt, pauses []models.AttemptPause, now time.Time) *AttemptTimer {
+	active, paused := activeTime(attempt.StartedAt, pauses, now)
+	timer := &AttemptTimer{
+		AttemptID:   attempt.ID,
+		StartedAt:   attempt.StartedAt,
+		ElapsedTime: models.SecondsOf(active),
+		PausedTime:  models.SecondsOf(paused),
+	}
+
+	for i := range pauses {
+		if pauses[i].ResumedAt == nil {
+			timer.IsPaused = true
+			timer.PausedAt = &pauses[i].PausedAt
+		}
+	}
+	return timer
+}
+
+// SubmittedTime returns the time played on an attempt submitted at now. An
+// attempt cannot be submitted while paused: its open pause would count as
+// paused time, so solving with the clock stopped would look instant.
+func SubmittedTime(startedAt time.Time, pauses []models.AttemptPause, now time.Time) (models.Seconds, error) {
+	for i := range pauses {
+		if pauses[i].ResumedAt == nil {
+			return 0, ErrAttemptPaused
+		}
+	}
+
+	active, _ := activeTime(startedAt, pauses, now)
+	return models.SecondsOf(active), nil
+}
+
+// activeTime splits the time between startedAt and now into time played and
+// time paused. A pause that is still open runs until now.
+func activeTime(startedAt time.Time, pauses []models.AttemptPause, now time.Time) (active, paused time.Duration) {
+	if !now.After(startedAt) {
+		return 0, 0
+	}
+
+	for _, pause := range pauses {
+		from := pause.PausedAt
+		if from.Before(startedAt) {
+			from = startedAt
+		}
+		to := now
+		if pause.ResumedAt != nil && pause.ResumedAt.Before(now) {
+			to = *pause.ResumedAt
+		}
+		if to.After(from) {
+			paused += to.Sub(from)
+		}
+	}
+
+	active = now.Sub(startedAt) - paused
+	if active < 0 {
+		active = 0
+	}
+	return active, paused
+}
+
+// timesDisagree reports whether a client's completion time is too far from
+// the server's to be explained by latency
+func timesDisagree(server, client models.Seconds) bool {
+	tolerance := time.Duration(math.Max(
+		float64(timerToleranceMin),
+		float64(server.Duration())*timerToleranceRate,
+	))
+	diff := server.Duration() - client.Duration()
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff > tolerance
+}
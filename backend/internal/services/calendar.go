@@ -0,0 +1,50 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"hh_puzzle/internal/models"
+)
+
+// DefaultTimezone is used for players who have not set a timezone
+const DefaultTimezone = "UTC"
+
+// LoadTimezone returns the location for an IANA timezone name, such as
+// "America/Los_Angeles". An empty name means DefaultTimezone.
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		name = DefaultTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", name)
+	}
+	return loc, nil
+}
+
+// UserLocation returns the location of a player's profile, falling back to
+// UTC when the stored timezone cannot be loaded
+func UserLocation(profile *models.UserProfile) *time.Location {
+	if profile == nil {
+		return time.UTC
+	}
+	loc, err := LoadTimezone(profile.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// LocalDate returns the calendar date of t in loc. The date is represented as
+// midnight UTC, matching how DATE columns are read back, so dates from
+// different timezones compare and step with AddDate without DST effects.
+func LocalDate(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// dateOf truncates a time to its UTC calendar date
+func dateOf(t time.Time) time.Time {
+	return LocalDate(t, time.UTC)
+}
@@ -0,0 +1,328 @@
+package services
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"hh_puzzle/internal/models"
+	"hh_puzzle/internal/repository"
+)
+
+// shareCodeAlphabet leaves out letters and digits that are easy to mix up
+const shareCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+const shareCodeLength = 8
+
+// CoopSplitRule describes how the points of a shared attempt are split
+const CoopSplitRule = "Points are split in proportion to the correct cells each player filled. " +
+	"Cells revealed by hints count for no one, and the host gets all points when no one filled a correct cell."
+
+// SharedAttempt is an attempt several players solve together
+type SharedAttempt struct {
+	AttemptID   uint                   `json:"attempt_id"`
+	PuzzleID    uint                   `json:"puzzle_id"`
+	HostID      uint                   `json:"host_id"`
+	ShareCode   string                 `json:"share_code"`
+	IsCompleted bool                   `json:"is_completed"`
+	Members     []models.AttemptMember `json:"members"`
+	MaxMembers  int                    `json:"max_members"`
+	SplitRule   string                 `json:"split_rule"`
+}
+
+// CoopService handles sharing attempts between players
+type CoopService interface {
+	ShareAttempt(userID, attemptID uint) (*SharedAttempt, error)
+	JoinAttempt(userID uint, code string) (*SharedAttempt, error)
+	GetSharedAttempt(userID, attemptID uint) (*SharedAttempt, error)
+}
+
+type coopService struct {
+	txManager          repository.TxManager
+	attemptRepo        repository.AttemptRepository
+	memberRepo         repository.AttemptMemberRepository
+	puzzleRepo         repository.PuzzleRepository
+	entitlementService EntitlementService
+}
+
+// NewCoopService creates a new co-op service
+func NewCoopService(
+	txManager repository.TxManager,
+	attemptRepo repository.AttemptRepository,
+	memberRepo repository.AttemptMemberRepository,
+	puzzleRepo repository.PuzzleRepository,
+	entitlementService EntitlementService,
+) CoopService {
+	return &coopService{
+		txManager:          txManager,
+		attemptRepo:        attemptRepo,
+		memberRepo:         memberRepo,
+		puzzleRepo:         puzzleRepo,
+		entitlementService: entitlementService,
+	}
+}
+
+// ShareAttempt gives the owner's unfinished attempt a share code that other
+// players join it with. Sharing an already shared attempt returns its code.
+func (s *coopService) ShareAttempt(userID, attemptID uint) (*SharedAttempt, error) {
+	attempt, err := s.findAttempt(attemptID)
+	if err != nil {
+		return nil, err
+	}
+	if attempt.UserID != userID {
+		return nil, ErrAttemptForbidden
+	}
+	if attempt.IsShared {
+		return s.sharedAttempt(attempt)
+	}
+	if attempt.IsCompleted {
+		return nil, ErrAttemptCompleted
+	}
+
+	code, err := newShareCode()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.txManager.Transaction(func(tx *repository.Tx) error {
+		shared, err := s.attemptRepo.WithTx(tx).Share(attempt, code)
+		if err != nil {
+			return fmt.Errorf("failed to share attempt: %w", err)
+		}
+		if !shared {
+			return nil
+		}
+
+		_, err = s.memberRepo.WithTx(tx).Add(&models.AttemptMember{
+			AttemptID: attempt.ID,
+			UserID:    userID,
+			Role:      models.MemberRoleHost,
+			JoinedAt:  time.Now(),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Another request shared or completed the attempt first
+	if !attempt.IsShared {
+		attempt, err = s.findAttempt(attemptID)
+		if err != nil {
+			return nil, err
+		}
+		if !attempt.IsShared {
+			return nil, ErrAttemptCompleted
+		}
+	}
+
+	return s.sharedAttempt(attempt)
+}
+
+// JoinAttempt adds the user to the unfinished attempt shared with the code.
+// Joining an attempt the user is already a member of changes nothing. As when
+// starting an attempt, the user must be entitled to the puzzle, and a puzzle
+// they already completed cannot earn them points again.
+func (s *coopService) JoinAttempt(userID uint, code string) (*SharedAttempt, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	var attempt *models.PuzzleAttempt
+	err := s.txManager.Transaction(func(tx *repository.Tx) error {
+		var err error
+		attempt, err = s.attemptRepo.WithTx(tx).FindByShareCodeForUpdate(code)
+		if err != nil {
+			if err.Error() == "attempt not found" {
+				return ErrShareCodeInvalid
+			}
+			return err
+		}
+
+		memberRepo := s.memberRepo.WithTx(tx)
+		members, err := memberRepo.FindByAttempt(attempt.ID)
+		if err != nil {
+			return err
+		}
+		for _, member := range members {
+			if member.UserID == userID {
+				return nil
+			}
+		}
+
+		if attempt.IsCompleted {
+			return ErrAttemptCompleted
+		}
+		if len(members) >= models.MaxAttemptMembers {
+			return ErrAttemptFull
+		}
+		if err := s.checkCanJoin(userID, attempt.PuzzleID); err != nil {
+			return err
+		}
+
+		_, err = memberRepo.Add(&models.AttemptMember{
+			AttemptID: attempt.ID,
+			UserID:    userID,
+			Role:      models.MemberRoleGuest,
+			JoinedAt:  time.Now(),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.sharedAttempt(attempt)
+}
+
+// GetSharedAttempt returns a shared attempt the user is a member of
+func (s *coopService) GetSharedAttempt(userID, attemptID uint) (*SharedAttempt, error) {
+	attempt, err := s.findAttempt(attemptID)
+	if err != nil {
+		return nil, err
+	}
+	if !attempt.IsShared {
+		if attempt.UserID != userID {
+			return nil, ErrAttemptForbidden
+		}
+		return nil, ErrAttemptNotShared
+	}
+
+	shared, err := s.sharedAttempt(attempt)
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range shared.Members {
+		if member.UserID == userID {
+			return shared, nil
+		}
+	}
+	return nil, ErrAttemptForbidden
+}
+
+// checkCanJoin runs the checks StartAttempt makes before a user plays a puzzle
+func (s *coopService) checkCanJoin(userID, puzzleID uint) error {
+	puzzle, err := s.puzzleRepo.FindByID(puzzleID)
+	if err != nil {
+		return err
+	}
+
+	allowed, err := s.entitlementService.CanPlayPuzzle(userID, puzzle)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrPuzzleLocked
+	}
+
+	latest, err := s.attemptRepo.FindLatestRun(userID, puzzleID)
+	if err != nil && err.Error() != "attempt not found" {
+		return err
+	}
+	if latest != nil && (latest.IsCompleted || latest.RunNumber > 1) {
+		return ErrPuzzleCompleted
+	}
+
+	// Solving it in another shared attempt counts as completing it too
+	completed, err := s.memberRepo.HasCompletedPuzzle(userID, puzzleID)
+	if err != nil {
+		return err
+	}
+	if completed {
+		return ErrPuzzleCompleted
+	}
+	return nil
+}
+
+func (s *coopService) findAttempt(attemptID uint) (*models.PuzzleAttempt, error) {
+	attempt, err := s.attemptRepo.FindByID(attemptID)
+	if err != nil {
+		if err.Error() == "attempt not found" {
+			return nil, ErrAttemptNotFound
+		}
+		return nil, err
+	}
+	return attempt, nil
+}
+
+func (s *coopService) sharedAttempt(attempt *models.PuzzleAttempt) (*SharedAttempt, error) {
+	members, err := s.memberRepo.FindByAttempt(attempt.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	shared := &SharedAttempt{
+		AttemptID:   attempt.ID,
+		PuzzleID:    attempt.PuzzleID,
+		HostID:      attempt.UserID,
+		IsCompleted: attempt.IsCompleted,
+		Members:     members,
+		MaxMembers:  models.MaxAttemptMembers,
+		SplitRule:   CoopSplitRule,
+	}
+	if attempt.ShareCode != nil {
+		shared.ShareCode = *attempt.ShareCode
+	}
+	return shared, nil
+}
+
+// newShareCode returns a random code from shareCodeAlphabet
+func newShareCode() (string, error) {
+	b := make([]byte, shareCodeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate share code: %w", err)
+	}
+	for i := range b {
+		b[i] = shareCodeAlphabet[int(b[i])%len(shareCodeAlphabet)]
+	}
+	return string(b), nil
+}
+
+// splitPoints shares a shared attempt's points between its members by the
+// correct cells each of them filled, recording both on the members. Points
+// left over from rounding go to the largest remainders, earlier members first.
+// When no member filled a correct cell the host gets everything.
+func splitPoints(total int, members []models.AttemptMember, authors models.JSONB, correct map[string]bool) {
+	cellsBy := make(map[uint]int)
+	for key, author := range authors {
+		if correct[key] {
+			cellsBy[uint(jsonInt(author))]++
+		}
+	}
+
+	filled := 0
+	for i := range members {
+		members[i].CorrectCells = cellsBy[members[i].UserID]
+		members[i].PointsEarned = 0
+		filled += members[i].CorrectCells
+	}
+
+	if filled == 0 {
+		for i := range members {
+			if members[i].Role == models.MemberRoleHost {
+				members[i].PointsEarned = total
+			}
+		}
+		return
+	}
+
+	left := total
+	remainders := make([]int, len(members))
+	for i := range members {
+		share := total * members[i].CorrectCells
+		members[i].PointsEarned = share / filled
+		remainders[i] = share % filled
+		left -= members[i].PointsEarned
+	}
+
+	order := make([]int, len(members))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]] > remainders[order[b]]
+	})
+	for _, i := range order[:left] {
+		members[i].PointsEarned++
+	}
+}
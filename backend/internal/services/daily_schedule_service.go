@@ -0,0 +1,179 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"hh_puzzle/internal/models"
+	"hh_puzzle/internal/repository"
+)
+
+// dailyDifficulties is the order daily challenges cycle through
+var dailyDifficulties = []string{"beginner", "intermediate", "expert"}
+
+// dailyRotationWindow is how many previous days are considered when
+// avoiding repeated decades and regions
+const dailyRotationWindow = 3
+
+// ScheduledDay is the daily challenge for one date
+type ScheduledDay struct {
+	Date       time.Time `json:"date"`
+	PuzzleID   uint      `json:"puzzle_id"`
+	Title      string    `json:"title"`
+	Difficulty string    `json:"difficulty"`
+	Decade     string    `json:"decade,omitempty"`
+	Region     string    `json:"region,omitempty"`
+	Existing   bool      `json:"existing"` // already scheduled before this run
+}
+
+// ScheduleGap is a date that could not be given a daily challenge
+type ScheduleGap struct {
+	Date   time.Time `json:"date"`
+	Reason string    `json:"reason"`
+}
+
+// DailySchedule is the outcome of a scheduling run
+type DailySchedule struct {
+	Days   []ScheduledDay `json:"days"`
+	Gaps   []ScheduleGap  `json:"gaps"`
+	DryRun bool           `json:"dry_run"`
+}
+
+// DailyScheduleService assigns puzzles to upcoming daily challenge dates
+type DailyScheduleService interface {
+	ScheduleDays(start time.Time, days int, dryRun bool) (*DailySchedule, error)
+}
+
+type dailyScheduleService struct {
+	puzzleRepo repository.PuzzleRepository
+}
+
+// NewDailyScheduleService creates a new daily schedule service
+func NewDailyScheduleService(puzzleRepo repository.PuzzleRepository) DailyScheduleService {
+	return &dailyScheduleService{
+		puzzleRepo: puzzleRepo,
+	}
+}
+
+// ScheduleDays fills every unscheduled date from start for the given number of
+// days. Difficulty follows a fixed daily cycle and the decade and region are
+// varied from the days before. Dates already scheduled are left alone. In dry
+// run mode the plan is returned without being saved.
+func (s *dailyScheduleService) ScheduleDays(start time.Time, days int, dryRun bool) (*DailySchedule, error) {
+	start = dateOf(start)
+	end := start.AddDate(0, 0, days-1)
+
+	existing, err := s.puzzleRepo.FindDailyChallengesBetween(start.AddDate(0, 0, -dailyRotationWindow), end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load scheduled challenges: %w", err)
+	}
+	scheduled := make(map[time.Time]models.Puzzle, len(existing))
+	for _, puzzle := range existing {
+		scheduled[dateOf(*puzzle.DailyChallengeDate)] = puzzle
+	}
+
+	candidates, err := s.puzzleRepo.FindDailyCandidates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load candidate puzzles: %w", err)
+	}
+
+	// Seed the rotation with the days just before the run, most recent first
+	var recent []models.Puzzle
+	for i := 1; i <= dailyRotationWindow; i++ {
+		if puzzle, ok := scheduled[start.AddDate(0, 0, -i)]; ok {
+			recent = append(recent, puzzle)
+		}
+	}
+
+	result := &DailySchedule{
+		Days:   []ScheduledDay{},
+		Gaps:   []ScheduleGap{},
+		DryRun: dryRun,
+	}
+
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		if puzzle, ok := scheduled[date]; ok {
+			result.Days = append(result.Days, newScheduledDay(date, puzzle, true))
+			recent = pushRecent(recent, puzzle)
+			continue
+		}
+
+		index := pickDailyPuzzle(candidates, dailyDifficultyFor(date), recent)
+		if index < 0 {
+			result.Gaps = append(result.Gaps, ScheduleGap{Date: date, Reason: "no unplayed puzzles left"})
+			continue
+		}
+		puzzle := candidates[index]
+		candidates = append(candidates[:index], candidates[index+1:]...)
+
+		if !dryRun {
+			if err := s.puzzleRepo.AssignDailyChallenge(puzzle.ID, date); err != nil {
+				result.Gaps = append(result.Gaps, ScheduleGap{
+					Date:   date,
+					Reason: fmt.Sprintf("failed to assign puzzle %d: %v", puzzle.ID, err),
+				})
+				continue
+			}
+		}
+
+		result.Days = append(result.Days, newScheduledDay(date, puzzle, false))
+		recent = pushRecent(recent, puzzle)
+	}
+
+	return result, nil
+}
+
+// dailyDifficultyFor returns the difficulty in the cycle for a date, so the
+// rotation stays the same whichever day a run starts on
+func dailyDifficultyFor(date time.Time) string {
+	day := int(date.Unix() / int64(24*time.Hour/time.Second))
+	return dailyDifficulties[day%len(dailyDifficulties)]
+}
+
+// pickDailyPuzzle returns the index of the candidate that best fits the day,
+// or -1 when there are none. A matching difficulty matters most, then a decade
+// and region that differ from recent days; ties go to the oldest puzzle.
+func pickDailyPuzzle(candidates []models.Puzzle, difficulty string, recent []models.Puzzle) int {
+	best, bestPenalty := -1, 0
+	for i, puzzle := range candidates {
+		penalty := 0
+		if puzzle.Difficulty != difficulty {
+			penalty += 100
+		}
+		for age, previous := range recent {
+			weight := dailyRotationWindow - age
+			if puzzle.Decade != "" && puzzle.Decade == previous.Decade {
+				penalty += weight
+			}
+			if puzzle.Region != "" && puzzle.Region == previous.Region {
+				penalty += weight
+			}
+		}
+
+		if best < 0 || penalty < bestPenalty {
+			best, bestPenalty = i, penalty
+		}
+	}
+	return best
+}
+
+// pushRecent adds a puzzle to the front of the rotation window
+func pushRecent(recent []models.Puzzle, puzzle models.Puzzle) []models.Puzzle {
+	recent = append([]models.Puzzle{puzzle}, recent...)
+	if len(recent) > dailyRotationWindow {
+		recent = recent[:dailyRotationWindow]
+	}
+	return recent
+}
+
+func newScheduledDay(date time.Time, puzzle models.Puzzle, existing bool) ScheduledDay {
+	return ScheduledDay{
+		Date:       date,
+		PuzzleID:   puzzle.ID,
+		Title:      puzzle.Title,
+		Difficulty: puzzle.Difficulty,
+		Decade:     puzzle.Decade,
+		Region:     puzzle.Region,
+		Existing:   existing,
+	}
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"time"
+
+	"hh_puzzle/internal/models"
+	"hh_puzzle/internal/repository"
+)
+
+// EntitlementService decides which puzzles and packs a user may play
+type EntitlementService interface {
+	CanPlayPuzzle(userID uint, puzzle *models.Puzzle) (bool, error)
+	HasPackAccess(userID uint, pack *models.PuzzlePack) (bool, error)
+}
+
+type entitlementService struct {
+	purchaseRepo        repository.PurchaseRepository
+	packRepo            repository.PuzzlePackRepository
+	userRepo            repository.UserRepository
+	subscriptionService SubscriptionService
+}
+
+// NewEntitlementService creates a new entitlement service
+func NewEntitlementService(
+	purchaseRepo repository.PurchaseRepository,
+	packRepo repository.PuzzlePackRepository,
+	userRepo repository.UserRepository,
+	subscriptionService SubscriptionService,
+) EntitlementService {
+	return &entitlementService{
+		purchaseRepo:        purchaseRepo,
+		packRepo:            packRepo,
+		userRepo:            userRepo,
+		subscriptionService: subscriptionService,
+	}
+}
+
+// CanPlayPuzzle reports whether the user may see and play the full puzzle.
+// Daily challenges are open to everyone once their date has come in the
+// user's timezone, and stay locked before then. Other puzzles outside a paid
+// pack are open to everyone.
+func (s *entitlementService) CanPlayPuzzle(userID uint, puzzle *models.Puzzle) (bool, error) {
+	if puzzle.IsDailyChallenge && puzzle.DailyChallengeDate != nil {
+		return s.dailyChallengeReleased(userID, *puzzle.DailyChallengeDate), nil
+	}
+	if puzzle.PuzzlePackID == nil {
+		return true, nil
+	}
+
+	pack := puzzle.PuzzlePack
+	if pack == nil {
+		var err error
+		pack, err = s.packRepo.FindByID(*puzzle.PuzzlePackID)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return s.HasPackAccess(userID, pack)
+}
+
+// dailyChallengeReleased reports whether a challenge date is today or earlier
+// on the user's local calendar
+func (s *entitlementService) dailyChallengeReleased(userID uint, date time.Time) bool {
+	var profile *models.UserProfile
+	if user, err := s.userRepo.GetWithProfile(userID); err == nil {
+		profile = user.Profile
+	}
+
+	today := LocalDate(time.Now(), UserLocation(profile))
+	return !dateOf(date).After(today)
+}
+
+// HasPackAccess reports whether the user owns the pack, either through a
+// completed purchase or an active subscription
+func (s *entitlementService) HasPackAccess(userID uint, pack *models.PuzzlePack) (bool, error) {
+	if pack.PriceUSD == 0 && !pack.IsSubscription {
+		return true, nil
+	}
+
+	purchased, err := s.purchaseRepo.HasCompletedPurchase(userID, pack.ID)
+	if err != nil || purchased {
+		return purchased, err
+	}
+
+	return s.subscriptionService.HasAccess(userID, pack.ID, time.Now())
+}
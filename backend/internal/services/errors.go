@@ -0,0 +1,37 @@
+package services
+
+import "errors"
+
+// Errors returned by services that handlers map to specific HTTP statuses
+var (
+	ErrAttemptNotFound  = errors.New("attempt not found")
+	ErrAttemptForbidden = errors.New("attempt belongs to another user")
+	ErrAttemptCompleted = errors.New("attempt already completed")
+	ErrAttemptNotPaused = errors.New("attempt is not paused")
+	ErrAttemptPaused    = errors.New("attempt is paused")
+	ErrPuzzleCompleted  = errors.New("puzzle already completed")
+	ErrProgressConflict = errors.New("attempt progress changed since the given version")
+	ErrInvalidCell      = errors.New("invalid cell")
+	ErrAttemptNotShared = errors.New("attempt is not shared")
+	ErrShareCodeInvalid = errors.New("no shared attempt with this code")
+	ErrAttemptFull      = errors.New("shared attempt has no room for more players")
+	ErrFactNotFound     = errors.New("fact not found")
+	ErrFactLocked       = errors.New("fact is locked")
+	ErrPuzzleLocked     = errors.New("puzzle requires a purchase or subscription")
+
+	ErrPackNotFound              = errors.New("puzzle pack not found")
+	ErrPackNotForSale            = errors.New("puzzle pack is free")
+	ErrPackAlreadyOwned          = errors.New("puzzle pack already owned")
+	ErrPurchaseNotFound          = errors.New("purchase not found")
+	ErrUnknownPaymentProvider    = errors.New("unknown payment provider")
+	ErrInvalidPurchaseTransition = errors.New("purchase cannot move to that status")
+	ErrSubscriptionNotFound      = errors.New("subscription not found")
+	ErrSubscriptionExpired       = errors.New("subscription has expired")
+	ErrSubscriptionConflict      = errors.New("subscription changed while it was being updated")
+
+	ErrFreezeLimitReached = errors.New("streak freeze limit reached")
+	ErrNotEnoughPoints    = errors.New("not enough points")
+
+	ErrScoreNotRecorded     = errors.New("attempt has no recorded score breakdown")
+	ErrUnknownScoringPolicy = errors.New("unknown scoring policy version")
+)
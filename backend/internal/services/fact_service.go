@@ -0,0 +1,131 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"hh_puzzle/internal/models"
+	"hh_puzzle/internal/repository"
+)
+
+// FactView is a fact as listed to a user. Locked facts are teasers without content.
+type FactView struct {
+	ID          uint       `json:"id"`
+	Title       string     `json:"title"`
+	Category    string     `json:"category,omitempty"`
+	UnlockType  string     `json:"unlock_type,omitempty"`
+	UnlockValue *int       `json:"unlock_value,omitempty"`
+	IsUnlocked  bool       `json:"is_unlocked"`
+	UnlockedAt  *time.Time `json:"unlocked_at,omitempty"`
+	Content     string     `json:"content,omitempty"`
+	ImageURL    string     `json:"image_url,omitempty"`
+	SourceURL   string     `json:"source_url,omitempty"`
+}
+
+// FactService handles hip-hop fact unlocking and browsing
+type FactService interface {
+	WithTx(tx *repository.Tx) FactService
+	EvaluateUnlocks(userID, puzzleID uint, totalPoints, streak int) ([]models.HipHopFact, error)
+	GetFacts(userID uint) ([]FactView, error)
+	GetFact(userID, factID uint) (*models.HipHopFact, error)
+}
+
+type factService struct {
+	factRepo repository.FactRepository
+}
+
+// NewFactService creates a new fact service
+func NewFactService(factRepo repository.FactRepository) FactService {
+	return &factService{
+		factRepo: factRepo,
+	}
+}
+
+// WithTx returns a copy of the service whose repositories run inside the transaction
+func (s *factService) WithTx(tx *repository.Tx) FactService {
+	return &factService{
+		factRepo: s.factRepo.WithTx(tx),
+	}
+}
+
+// EvaluateUnlocks checks every unlock rule against the user's new totals and
+// returns the facts unlocked by this call
+func (s *factService) EvaluateUnlocks(userID, puzzleID uint, totalPoints, streak int) ([]models.HipHopFact, error) {
+	candidates, err := s.factRepo.FindUnlockable(userID, puzzleID, totalPoints, streak)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	unlocked := []models.HipHopFact{}
+	for _, fact := range candidates {
+		created, err := s.factRepo.Unlock(userID, fact.ID, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unlock fact: %w", err)
+		}
+		// Another request may have unlocked it first
+		if created {
+			unlocked = append(unlocked, fact)
+		}
+	}
+
+	return unlocked, nil
+}
+
+func (s *factService) GetFacts(userID uint) ([]FactView, error) {
+	facts, err := s.factRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	unlocked, err := s.factRepo.FindUnlockedByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	unlockedAt := make(map[uint]time.Time, len(unlocked))
+	for _, u := range unlocked {
+		unlockedAt[u.FactID] = u.UnlockedAt
+	}
+
+	views := make([]FactView, len(facts))
+	for i, fact := range facts {
+		views[i] = FactView{
+			ID:          fact.ID,
+			Title:       fact.Title,
+			Category:    fact.Category,
+			UnlockType:  fact.UnlockType,
+			UnlockValue: fact.UnlockValue,
+		}
+
+		if at, ok := unlockedAt[fact.ID]; ok {
+			views[i].IsUnlocked = true
+			views[i].UnlockedAt = &at
+			views[i].Content = fact.Content
+			views[i].ImageURL = fact.ImageURL
+			views[i].SourceURL = fact.SourceURL
+		}
+	}
+
+	return views, nil
+}
+
+func (s *factService) GetFact(userID, factID uint) (*models.HipHopFact, error) {
+	fact, err := s.factRepo.FindByID(factID)
+	if err != nil {
+		if err.Error() == "fact not found" {
+			return nil, ErrFactNotFound
+		}
+		return nil, err
+	}
+
+	isUnlocked, err := s.factRepo.IsUnlocked(userID, factID)
+	if err != nil {
+		return nil, err
+	}
+	if !isUnlocked {
+		return nil, ErrFactLocked
+	}
+
+	return fact, nil
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"hh_puzzle/internal/models"
+	"hh_puzzle/internal/repository"
+)
+
+// LeaderboardPage contains one page of a leaderboard and the caller's own standing
+type LeaderboardPage struct {
+	WeekStartDate *time.Time                       `json:"week_start_date,omitempty"`
+	WeekEndDate   *time.Time                       `json:"week_end_date,omitempty"`
+	Entries       []repository.LeaderboardStanding `json:"entries"`
+	Me            *repository.LeaderboardStanding  `json:"me,omitempty"`
+}
+
+// LeaderboardService handles leaderboard business logic
+type LeaderboardService interface {
+	WithTx(tx *repository.Tx) LeaderboardService
+	RecordCompletion(userID uint, points int, completionTime models.Seconds, completedAt time.Time) error
+	CloseFinishedWeeks(now time.Time) error
+	GetWeeklyLeaderboard(userID uint, date time.Time, page, perPage int) (*LeaderboardPage, *Pagination, error)
+	GetAllTimeLeaderboard(userID uint, page, perPage int) (*LeaderboardPage, *Pagination, error)
+}
+
+type leaderboardService struct {
+	leaderboardRepo repository.LeaderboardRepository
+}
+
+// NewLeaderboardService creates a new leaderboard service
+func NewLeaderboardService(leaderboardRepo repository.LeaderboardRepository) LeaderboardService {
+	return &leaderboardService{
+		leaderboardRepo: leaderboardRepo,
+	}
+}
+
+// WithTx returns a copy of the service whose repositories run inside the transaction
+func (s *leaderboardService) WithTx(tx *repository.Tx) LeaderboardService {
+	return &leaderboardService{
+		leaderboardRepo: s.leaderboardRepo.WithTx(tx),
+	}
+}
+
+// RecordCompletion adds a completed puzzle to the user's row for the current week
+func (s *leaderboardService) RecordCompletion(userID uint, points int, completionTime models.Seconds, completedAt time.Time) error {
+	weekStart, weekEnd := WeekBounds(completedAt)
+
+	entry, err := s.leaderboardRepo.FindByUserAndWeek(userID, weekStart)
+	if err != nil && err.Error() != "leaderboard entry not found" {
+		return err
+	}
+
+	if entry == nil {
+		entry = &models.Leaderboard{
+			UserID:        userID,
+			WeekStartDate: weekStart,
+			WeekEndDate:   weekEnd,
+		}
+	}
+
+	// Keep a running average of completion times
+	average := completionTime
+	if entry.AverageCompletionTime != nil && entry.PuzzlesCompleted > 0 {
+		total := *entry.AverageCompletionTime*models.Seconds(entry.PuzzlesCompleted) + completionTime
+		average = total / models.Seconds(entry.PuzzlesCompleted+1)
+	}
+
+	entry.TotalPoints += points
+	entry.PuzzlesCompleted++
+	entry.AverageCompletionTime = &average
+
+	if entry.ID == 0 {
+		return s.leaderboardRepo.Create(entry)
+	}
+	return s.leaderboardRepo.Update(entry)
+}
+
+// CloseFinishedWeeks stores final ranks for every week that has ended
+func (s *leaderboardService) CloseFinishedWeeks(now time.Time) error {
+	currentWeekStart, _ := WeekBounds(now)
+
+	weeks, err := s.leaderboardRepo.FindUnrankedWeeksBefore(currentWeekStart)
+	if err != nil {
+		return err
+	}
+
+	for _, weekStart := range weeks {
+		if err := s.leaderboardRepo.RankWeek(weekStart); err != nil {
+			return fmt.Errorf("failed to rank week of %s: %w", weekStart.Format("2006-01-02"), err)
+		}
+	}
+
+	return nil
+}
+
+func (s *leaderboardService) GetWeeklyLeaderboard(userID uint, date time.Time, page, perPage int) (*LeaderboardPage, *Pagination, error) {
+	page, perPage = normalizePage(page, perPage)
+	weekStart, weekEnd := WeekBounds(date)
+
+	entries, err := s.leaderboardRepo.FindWeekStandings(weekStart, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	total, err := s.leaderboardRepo.CountWeek(weekStart)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The caller may not have played that week
+	me, _ := s.leaderboardRepo.FindUserWeekStanding(userID, weekStart)
+
+	result := &LeaderboardPage{
+		WeekStartDate: &weekStart,
+		WeekEndDate:   &weekEnd,
+		Entries:       entries,
+		Me:            me,
+	}
+
+	return result, newPagination(page, perPage, total), nil
+}
+
+func (s *leaderboardService) GetAllTimeLeaderboard(userID uint, page, perPage int) (*LeaderboardPage, *Pagination, error) {
+	page, perPage = normalizePage(page, perPage)
+
+	entries, err := s.leaderboardRepo.FindAllTimeStandings(perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	total, err := s.leaderboardRepo.CountAllTime()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The caller may not have completed a puzzle yet
+	me, _ := s.leaderboardRepo.FindUserAllTimeStanding(userID)
+
+	result := &LeaderboardPage{
+		Entries: entries,
+		Me:      me,
+	}
+
+	return result, newPagination(page, perPage, total), nil
+}
+
+// WeekBounds returns the Monday and Sunday (UTC dates) of the week containing t
+func WeekBounds(t time.Time) (time.Time, time.Time) {
+	t = t.UTC()
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	start := time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 0, 6)
+}
+
+// normalizePage applies default pagination values
+func normalizePage(page, perPage int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+	return page, perPage
+}
+
+// newPagination builds pagination metadata for a result set
+func newPagination(page, perPage int, total int64) *Pagination {
+	return &Pagination{
+		Page:       page,
+		PerPage:    perPage,
+		Total:      total,
+		TotalPages: int(math.Ceil(float64(total) / float64(perPage))),
+	}
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"fmt"
+	"math/rand"
+
+	"hh_puzzle/internal/models"
+	"hh_puzzle/internal/repository"
+)
+
+// difficultyMoods lists the track moods that suit each puzzle difficulty
+var difficultyMoods = map[string][]string{
+	"beginner":     {models.MoodChill},
+	"intermediate": {models.MoodChill, models.MoodFocused},
+	"expert":       {models.MoodFocused, models.MoodEnergetic},
+}
+
+// Playlist is a shuffled queue of tracks to play during a puzzle
+type Playlist struct {
+	PuzzleID     uint                `json:"puzzle_id"`
+	Difficulty   string              `json:"difficulty"`
+	Moods        []string            `json:"moods"`
+	MusicEnabled bool                `json:"music_enabled"`
+	MusicVolume  int                 `json:"music_volume"`
+	Tracks       []models.MusicTrack `json:"tracks"`
+}
+
+// MusicService handles the background music catalog
+type MusicService interface {
+	GetTracks(mood string, active *bool) ([]models.MusicTrack, error)
+	GetPlaylist(userID, puzzleID uint) (*Playlist, error)
+	ReportPlay(trackID uint) error
+	CreateTrack(track *models.MusicTrack) error
+	UpdateTrack(trackID uint, update *models.MusicTrack) (*models.MusicTrack, error)
+	DeleteTrack(trackID uint) error
+}
+
+type musicService struct {
+	musicRepo  repository.MusicRepository
+	puzzleRepo repository.PuzzleRepository
+	userRepo   repository.UserRepository
+}
+
+// NewMusicService creates a new music service
+func NewMusicService(
+	musicRepo repository.MusicRepository,
+	puzzleRepo repository.PuzzleRepository,
+	userRepo repository.UserRepository,
+) MusicService {
+	return &musicService{
+		musicRepo:  musicRepo,
+		puzzleRepo: puzzleRepo,
+		userRepo:   userRepo,
+	}
+}
+
+func (s *musicService) GetTracks(mood string, active *bool) ([]models.MusicTrack, error) {
+	return s.musicRepo.FindTracks(mood, active)
+}
+
+// GetPlaylist shuffles the active tracks whose mood suits the puzzle's
+// difficulty, falling back to every active track when none match
+func (s *musicService) GetPlaylist(userID, puzzleID uint) (*Playlist, error) {
+	puzzle, err := s.puzzleRepo.FindByID(puzzleID)
+	if err != nil {
+		return nil, err
+	}
+
+	moods := difficultyMoods[puzzle.Difficulty]
+	tracks, err := s.musicRepo.FindActiveByMoods(moods)
+	if err != nil {
+		return nil, err
+	}
+	if len(tracks) == 0 && len(moods) > 0 {
+		if tracks, err = s.musicRepo.FindActiveByMoods(nil); err != nil {
+			return nil, err
+		}
+	}
+
+	rand.Shuffle(len(tracks), func(i, j int) {
+		tracks[i], tracks[j] = tracks[j], tracks[i]
+	})
+
+	playlist := &Playlist{
+		PuzzleID:     puzzle.ID,
+		Difficulty:   puzzle.Difficulty,
+		Moods:        moods,
+		MusicEnabled: true,
+		MusicVolume:  70,
+		Tracks:       tracks,
+	}
+
+	// Pass the player's saved preferences along with the queue
+	user, err := s.userRepo.GetWithProfile(userID)
+	if err == nil && user.Profile != nil {
+		playlist.MusicEnabled = user.Profile.MusicEnabled
+		playlist.MusicVolume = user.Profile.MusicVolume
+	}
+
+	return playlist, nil
+}
+
+func (s *musicService) ReportPlay(trackID uint) error {
+	return s.musicRepo.IncrementPlayCount(trackID)
+}
+
+func (s *musicService) CreateTrack(track *models.MusicTrack) error {
+	if err := validateTrack(track); err != nil {
+		return err
+	}
+	track.ID = 0
+	track.PlayCount = 0
+	return s.musicRepo.Create(track)
+}
+
+// UpdateTrack replaces a track's catalog fields, keeping its play count
+func (s *musicService) UpdateTrack(trackID uint, update *models.MusicTrack) (*models.MusicTrack, error) {
+	if err := validateTrack(update); err != nil {
+		return nil, err
+	}
+
+	track, err := s.musicRepo.FindByID(trackID)
+	if err != nil {
+		return nil, err
+	}
+
+	track.Title = update.Title
+	track.Artist = update.Artist
+	track.FileURL = update.FileURL
+	track.FileSizeKB = update.FileSizeKB
+	track.DurationSeconds = update.DurationSeconds
+	track.Genre = update.Genre
+	track.Mood = update.Mood
+	track.BPM = update.BPM
+	track.IsActive = update.IsActive
+
+	if err := s.musicRepo.Update(track); err != nil {
+		return nil, fmt.Errorf("failed to update track: %w", err)
+	}
+	return track, nil
+}
+
+func (s *musicService) DeleteTrack(trackID uint) error {
+	return s.musicRepo.Delete(trackID)
+}
+
+// validateTrack checks the fields an admin supplies for a track
+func validateTrack(track *models.MusicTrack) error {
+	switch track.Mood {
+	case "", models.MoodChill, models.MoodEnergetic, models.MoodFocused:
+	default:
+		return fmt.Errorf("invalid mood %q", track.Mood)
+	}
+	if track.BPM != nil && *track.BPM <= 0 {
+		return fmt.Errorf("bpm must be positive")
+	}
+	return nil
+}
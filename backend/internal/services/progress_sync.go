@@ -0,0 +1,145 @@
+package services
+
+import (
+	"fmt"
+	"unicode"
+
+	"hh_puzzle/internal/models"
+)
+
+// maxProgressWrites is how often a merged progress write is retried when other
+// writes keep landing first
+const maxProgressWrites = 3
+
+// ProgressDelta is the part of an attempt's grid that changed after a version.
+// A client that applies Cells is in sync with the server at Version.
+type ProgressDelta struct {
+	AttemptID uint              `json:"attempt_id"`
+	Version   int               `json:"version"`
+	Since     int               `json:"since"`
+	Cells     map[string]string `json:"cells"` // "x,y" -> letter, "" for cleared cells
+}
+
+// progressSince returns the cells changed after a version. A version of 0, or
+// one the client cannot have seen, returns the whole grid.
+func progressSince(attempt *models.PuzzleAttempt, since int) *ProgressDelta {
+	if since < 0 || since > attempt.Version {
+		since = 0
+	}
+
+	delta := &ProgressDelta{
+		AttemptID: attempt.ID,
+		Version:   attempt.Version,
+		Since:     since,
+		Cells:     make(map[string]string),
+	}
+
+	if since == 0 {
+		for key, letter := range entriesFromState(attempt.CurrentState) {
+			delta.Cells[key] = letter
+		}
+		return delta
+	}
+
+	for key, version := range attempt.CellVersions {
+		if jsonInt(version) > since {
+			letter, _ := attempt.CurrentState[key].(string)
+			delta.Cells[key] = letter
+		}
+	}
+	return delta
+}
+
+// setCells writes cells into the attempt's state as one new version. An empty
+// letter clears the cell. Cleared cells leave the state but keep their
+// version, so deltas report them. On shared attempts each filled cell is
+// credited to author; cells written with no author, like hint reveals, are
+// credited to no one. It returns the number of cells changed.
+func setCells(attempt *models.PuzzleAttempt, cells map[string]string, author uint) int {
+	if attempt.CurrentState == nil {
+		attempt.CurrentState = models.JSONB{}
+	}
+	if attempt.CellVersions == nil {
+		attempt.CellVersions = models.JSONB{}
+	}
+	if attempt.IsShared && attempt.CellAuthors == nil {
+		attempt.CellAuthors = models.JSONB{}
+	}
+
+	version := attempt.Version + 1
+	changed := 0
+	for key, letter := range cells {
+		letter = normalizeEntry(letter)
+		current, _ := attempt.CurrentState[key].(string)
+		if current == letter {
+			continue
+		}
+
+		if letter == "" {
+			delete(attempt.CurrentState, key)
+		} else {
+			attempt.CurrentState[key] = letter
+		}
+		attempt.CellVersions[key] = version
+		if attempt.IsShared {
+			if letter == "" || author == 0 {
+				delete(attempt.CellAuthors, key)
+			} else {
+				attempt.CellAuthors[key] = author
+			}
+		}
+		changed++
+	}
+
+	if changed > 0 {
+		attempt.Version = version
+	}
+	return changed
+}
+
+// stateChanges lists the cells that turn one state into another, with an
+// empty letter for cells the new state leaves out
+func stateChanges(from, to models.JSONB) map[string]string {
+	cells := entriesFromState(to)
+	for key := range entriesFromState(from) {
+		if _, ok := cells[key]; !ok {
+			cells[key] = ""
+		}
+	}
+	return cells
+}
+
+// validateCells checks that every cell is an open square of the grid and
+// every letter a single letter or empty
+func validateCells(puzzle *models.Puzzle, cells map[string]string) error {
+	grid := puzzle.GridData
+	for key, letter := range cells {
+		var x, y int
+		if _, err := fmt.Sscanf(key, "%d,%d", &x, &y); err != nil || cellKey(x, y) != key {
+			return fmt.Errorf("%w: %q is not an x,y cell", ErrInvalidCell, key)
+		}
+		if !grid.InBounds(x, y) || grid.Blocks[y][x] {
+			return fmt.Errorf("%w: %s is not an open square", ErrInvalidCell, key)
+		}
+
+		letter = normalizeEntry(letter)
+		runes := []rune(letter)
+		if len(runes) > 1 || (len(runes) == 1 && !unicode.IsLetter(runes[0])) {
+			return fmt.Errorf("%w: %s must be a single letter", ErrInvalidCell, key)
+		}
+	}
+	return nil
+}
+
+// jsonInt reads a version or author ID stored in a JSONB cell map
+func jsonInt(value interface{}) int {
+	switch v := value.(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	case uint:
+		return int(v)
+	}
+	return 0
+}
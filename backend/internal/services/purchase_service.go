@@ -0,0 +1,229 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"hh_puzzle/internal/models"
+	"hh_puzzle/internal/payments"
+	"hh_puzzle/internal/repository"
+)
+
+// purchaseTransitions lists the statuses each purchase status may move to
+var purchaseTransitions = map[string][]string{
+	models.PurchaseStatusPending:   {models.PurchaseStatusCompleted, models.PurchaseStatusFailed},
+	models.PurchaseStatusCompleted: {models.PurchaseStatusRefunded},
+}
+
+// CheckoutResult is a pending purchase and where the client should pay for it
+type CheckoutResult struct {
+	Purchase    *models.Purchase `json:"purchase"`
+	CheckoutURL string           `json:"checkout_url"`
+}
+
+// PurchaseService handles buying puzzle packs through payment providers
+type PurchaseService interface {
+	StartPurchase(userID, packID uint, providerName string) (*CheckoutResult, error)
+	HandleWebhook(providerName string, payload []byte, signature string) (*models.Purchase, error)
+	RefundPurchase(purchaseID uint) (*models.Purchase, error)
+	GetUserPurchases(userID uint) ([]models.Purchase, error)
+}
+
+type purchaseService struct {
+	purchaseRepo        repository.PurchaseRepository
+	packRepo            repository.PuzzlePackRepository
+	entitlementService  EntitlementService
+	subscriptionService SubscriptionService
+
+	providers       map[string]payments.Provider
+	defaultProvider string
+}
+
+// NewPurchaseService creates a new purchase service. The first provider is
+// used when a purchase does not name one.
+func NewPurchaseService(
+	purchaseRepo repository.PurchaseRepository,
+	packRepo repository.PuzzlePackRepository,
+	entitlementService EntitlementService,
+	subscriptionService SubscriptionService,
+	providers ...payments.Provider,
+) PurchaseService {
+	s := &purchaseService{
+		purchaseRepo:        purchaseRepo,
+		packRepo:            packRepo,
+		entitlementService:  entitlementService,
+		subscriptionService: subscriptionService,
+		providers:           make(map[string]payments.Provider, len(providers)),
+	}
+	for i, provider := range providers {
+		if i == 0 {
+			s.defaultProvider = provider.Name()
+		}
+		s.providers[provider.Name()] = provider
+	}
+	return s
+}
+
+// StartPurchase creates a pending purchase for a pack and opens a checkout with the provider
+func (s *purchaseService) StartPurchase(userID, packID uint, providerName string) (*CheckoutResult, error) {
+	provider, err := s.provider(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	pack, err := s.packRepo.FindByID(packID)
+	if err != nil {
+		return nil, ErrPackNotFound
+	}
+	if pack.PriceUSD <= 0 {
+		return nil, ErrPackNotForSale
+	}
+
+	owned, err := s.entitlementService.HasPackAccess(userID, pack)
+	if err != nil {
+		return nil, err
+	}
+	if owned {
+		return nil, ErrPackAlreadyOwned
+	}
+
+	session, err := provider.CreateCheckout(payments.CheckoutRequest{
+		UserID:       userID,
+		PuzzlePackID: pack.ID,
+		Description:  pack.Name,
+		AmountUSD:    pack.PriceUSD,
+		Currency:     "USD",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start checkout: %w", err)
+	}
+
+	purchase := &models.Purchase{
+		UserID:          userID,
+		PuzzlePackID:    &pack.ID,
+		AmountUSD:       pack.PriceUSD,
+		Currency:        "USD",
+		PaymentProvider: provider.Name(),
+		TransactionID:   session.TransactionID,
+		IsSubscription:  pack.IsSubscription,
+		Status:          models.PurchaseStatusPending,
+	}
+	if err := s.purchaseRepo.Create(purchase); err != nil {
+		return nil, fmt.Errorf("failed to create purchase: %w", err)
+	}
+
+	return &CheckoutResult{
+		Purchase:    purchase,
+		CheckoutURL: session.CheckoutURL,
+	}, nil
+}
+
+// HandleWebhook verifies a provider callback and applies its status change.
+// Repeated deliveries of the same event leave the purchase unchanged.
+func (s *purchaseService) HandleWebhook(providerName string, payload []byte, signature string) (*models.Purchase, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, ErrUnknownPaymentProvider
+	}
+
+	event, err := provider.VerifyEvent(payload, signature)
+	if err != nil {
+		return nil, err
+	}
+
+	purchase, err := s.purchaseRepo.FindByTransactionID(provider.Name(), event.TransactionID)
+	if err != nil {
+		return nil, ErrPurchaseNotFound
+	}
+
+	if event.Status == payments.EventRenewed {
+		// The period end identifies the renewal, so a redelivery is a no-op
+		if event.PeriodEnd == nil {
+			return nil, payments.ErrMissingPeriodEnd
+		}
+		if err := s.subscriptionService.Renew(purchase, *event.PeriodEnd); err != nil {
+			return nil, err
+		}
+		return purchase, nil
+	}
+
+	return s.transition(purchase, event.Status, time.Now())
+}
+
+// RefundPurchase refunds a completed purchase with its provider
+func (s *purchaseService) RefundPurchase(purchaseID uint) (*models.Purchase, error) {
+	purchase, err := s.purchaseRepo.FindByID(purchaseID)
+	if err != nil {
+		return nil, ErrPurchaseNotFound
+	}
+	if purchase.Status != models.PurchaseStatusCompleted {
+		return nil, ErrInvalidPurchaseTransition
+	}
+
+	provider, ok := s.providers[purchase.PaymentProvider]
+	if !ok {
+		return nil, ErrUnknownPaymentProvider
+	}
+	if err := provider.Refund(purchase.TransactionID, purchase.AmountUSD); err != nil {
+		return nil, fmt.Errorf("failed to refund purchase: %w", err)
+	}
+
+	return s.transition(purchase, models.PurchaseStatusRefunded, time.Now())
+}
+
+func (s *purchaseService) GetUserPurchases(userID uint) ([]models.Purchase, error) {
+	return s.purchaseRepo.FindByUser(userID)
+}
+
+// transition moves a purchase to a new status if the lifecycle allows it
+func (s *purchaseService) transition(purchase *models.Purchase, status string, now time.Time) (*models.Purchase, error) {
+	if purchase.Status == status {
+		return purchase, nil
+	}
+	if !canTransition(purchase.Status, status) {
+		return nil, ErrInvalidPurchaseTransition
+	}
+
+	from := purchase.Status
+	purchase.Status = status
+	if purchase.IsSubscription {
+		switch status {
+		case models.PurchaseStatusCompleted:
+			s.subscriptionService.Start(purchase, now)
+		case models.PurchaseStatusRefunded:
+			purchase.SubscriptionStatus = models.SubscriptionStatusExpired
+		}
+	}
+
+	updated, err := s.purchaseRepo.UpdateFromStatus(purchase, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update purchase: %w", err)
+	}
+	if !updated {
+		// A concurrent delivery changed the purchase first
+		return s.purchaseRepo.FindByID(purchase.ID)
+	}
+
+	return purchase, nil
+}
+
+// provider returns the named provider, or the default when no name is given
+func (s *purchaseService) provider(name string) (payments.Provider, error) {
+	if name == "" {
+		name = s.defaultProvider
+	}
+	provider, ok := s.providers[name]
+	if !ok {
+		return nil, ErrUnknownPaymentProvider
+	}
+	return provider, nil
+}
+
+func canTransition(from, to string) bool {
+	for _, allowed := range purchaseTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
@@ -1,7 +1,6 @@
 package services
 
 import (
-	"errors"
 	"math"
 	"time"
 
@@ -9,6 +8,10 @@ import (
 	"hh_puzzle/internal/repository"
 )
 
+// ParTimeMinSamples is how many fully correct completions a puzzle needs
+// before its par time is derived from them instead of its difficulty
+const ParTimeMinSamples = 20
+
 // PuzzleFilters contains filter parameters for puzzle queries
 type PuzzleFilters struct {
 	Difficulty string
@@ -28,23 +31,50 @@ type Pagination struct {
 	TotalPages int   `json:"total_pages"`
 }
 
+// PackDetail is a puzzle pack with the caller's ownership and progress
+type PackDetail struct {
+	models.PuzzlePack
+	Puzzles            []*PlayerPuzzle  `json:"puzzles,omitempty"`
+	Previews           []*PuzzlePreview `json:"previews,omitempty"` // listed instead of puzzles until the pack is owned
+	IsOwned            bool             `json:"is_owned"`
+	CompletedPuzzles   int              `json:"completed_puzzles"`
+	ProgressPercentage float64          `json:"progress_percentage"`
+}
+
 // PuzzleService handles puzzle-related business logic
 type PuzzleService interface {
 	GetPuzzleByID(puzzleID uint) (*models.Puzzle, error)
-	GetDailyChallenge() (*models.Puzzle, error)
+	GetPuzzleForUser(userID, puzzleID uint) (*models.Puzzle, error)
+	GetDailyChallenge(userID uint) (*models.Puzzle, error)
 	GetPuzzlesByFilters(filters PuzzleFilters) ([]models.Puzzle, *Pagination, error)
-	GetPuzzlePack(packID uint) (*models.PuzzlePack, error)
-	GetAvailablePacks() ([]models.PuzzlePack, error)
+	GetPuzzleViews(userID uint, puzzles []models.Puzzle) ([]interface{}, error)
+	GetPuzzlePack(userID, packID uint) (*PackDetail, error)
+	GetAvailablePacks(categoryType, categoryValue string) ([]models.PuzzlePack, error)
+	RecalculateParTimes() (int, error)
 }
 
 type puzzleService struct {
-	puzzleRepo repository.PuzzleRepository
+	puzzleRepo         repository.PuzzleRepository
+	packRepo           repository.PuzzlePackRepository
+	attemptRepo        repository.AttemptRepository
+	userRepo           repository.UserRepository
+	entitlementService EntitlementService
 }
 
 // NewPuzzleService creates a new puzzle service
-func NewPuzzleService(puzzleRepo repository.PuzzleRepository) PuzzleService {
+func NewPuzzleService(
+	puzzleRepo repository.PuzzleRepository,
+	packRepo repository.PuzzlePackRepository,
+	attemptRepo repository.AttemptRepository,
+	userRepo repository.UserRepository,
+	entitlementService EntitlementService,
+) PuzzleService {
 	return &puzzleService{
-		puzzleRepo: puzzleRepo,
+		puzzleRepo:         puzzleRepo,
+		packRepo:           packRepo,
+		attemptRepo:        attemptRepo,
+		userRepo:           userRepo,
+		entitlementService: entitlementService,
 	}
 }
 
@@ -52,8 +82,34 @@ func (s *puzzleService) GetPuzzleByID(puzzleID uint) (*models.Puzzle, error) {
 	return s.puzzleRepo.FindByID(puzzleID)
 }
 
-func (s *puzzleService) GetDailyChallenge() (*models.Puzzle, error) {
-	today := time.Now().Truncate(24 * time.Hour)
+// GetPuzzleForUser returns a puzzle the user is entitled to play. When the
+// puzzle is locked it is still returned, together with ErrPuzzleLocked, so
+// callers can show a preview.
+func (s *puzzleService) GetPuzzleForUser(userID, puzzleID uint) (*models.Puzzle, error) {
+	puzzle, err := s.puzzleRepo.FindByID(puzzleID)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, err := s.entitlementService.CanPlayPuzzle(userID, puzzle)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return puzzle, ErrPuzzleLocked
+	}
+
+	return puzzle, nil
+}
+
+// GetDailyChallenge returns the challenge for the user's local calendar day
+func (s *puzzleService) GetDailyChallenge(userID uint) (*models.Puzzle, error) {
+	var profile *models.UserProfile
+	if user, err := s.userRepo.GetWithProfile(userID); err == nil {
+		profile = user.Profile
+	}
+
+	today := LocalDate(time.Now(), UserLocation(profile))
 	return s.puzzleRepo.FindDailyChallenge(today)
 }
 
@@ -100,14 +156,121 @@ func (s *puzzleService) GetPuzzlesByFilters(filters PuzzleFilters) ([]models.Puz
 	return puzzles, pagination, nil
 }
 
-func (s *puzzleService) GetPuzzlePack(packID uint) (*models.PuzzlePack, error) {
-	// Note: This would need a PuzzlePackRepository method
-	// For now, return error
-	return nil, errors.New("not implemented")
+// GetPuzzleViews builds the list view of puzzles for the user: the player view
+// of each puzzle they may play, and a locked preview of the rest
+func (s *puzzleService) GetPuzzleViews(userID uint, puzzles []models.Puzzle) ([]interface{}, error) {
+	// Pack access is looked up once per pack rather than once per puzzle
+	packAccess := make(map[uint]bool)
+	views := make([]interface{}, len(puzzles))
+	for i := range puzzles {
+		puzzle := &puzzles[i]
+
+		allowed, cached := packAccess[packIDOf(puzzle)]
+		if !cached || puzzle.IsDailyChallenge {
+			var err error
+			allowed, err = s.entitlementService.CanPlayPuzzle(userID, puzzle)
+			if err != nil {
+				return nil, err
+			}
+			if !puzzle.IsDailyChallenge {
+				packAccess[packIDOf(puzzle)] = allowed
+			}
+		}
+
+		if allowed {
+			views[i] = NewPlayerPuzzle(puzzle)
+		} else {
+			views[i] = NewPuzzlePreview(puzzle)
+		}
+	}
+	return views, nil
+}
+
+// packIDOf returns the puzzle's pack ID, or 0 for a puzzle outside any pack
+func packIDOf(puzzle *models.Puzzle) uint {
+	if puzzle.PuzzlePackID == nil {
+		return 0
+	}
+	return *puzzle.PuzzlePackID
 }
 
-func (s *puzzleService) GetAvailablePacks() ([]models.PuzzlePack, error) {
-	// Note: This would need a PuzzlePackRepository method
-	// For now, return error
-	return nil, errors.New("not implemented")
+func (s *puzzleService) GetPuzzlePack(userID, packID uint) (*PackDetail, error) {
+	pack, err := s.packRepo.FindByID(packID)
+	if err != nil {
+		return nil, err
+	}
+
+	isOwned, err := s.entitlementService.HasPackAccess(userID, pack)
+	if err != nil {
+		return nil, err
+	}
+
+	completed, err := s.attemptRepo.CountCompletedInPack(userID, packID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Puzzles are listed without their solutions
+	puzzles := pack.Puzzles
+	pack.Puzzles = nil
+	pack.PuzzleCount = len(puzzles)
+
+	detail := &PackDetail{
+		PuzzlePack:       *pack,
+		IsOwned:          isOwned,
+		CompletedPuzzles: int(completed),
+	}
+	if isOwned {
+		detail.Puzzles = NewPlayerPuzzles(puzzles)
+	} else {
+		detail.Previews = NewPuzzlePreviews(puzzles)
+	}
+	if len(puzzles) > 0 {
+		detail.ProgressPercentage = float64(completed) / float64(len(puzzles)) * 100
+	}
+
+	return detail, nil
+}
+
+func (s *puzzleService) GetAvailablePacks(categoryType, categoryValue string) ([]models.PuzzlePack, error) {
+	packs, err := s.packRepo.FindActive(categoryType, categoryValue)
+	if err != nil {
+		return nil, err
+	}
+	if len(packs) == 0 {
+		return packs, nil
+	}
+
+	// Puzzle counts come from the member puzzles rather than the stored column
+	packIDs := make([]uint, len(packs))
+	for i, pack := range packs {
+		packIDs[i] = pack.ID
+	}
+	counts, err := s.packRepo.CountPuzzles(packIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range packs {
+		packs[i].PuzzleCount = counts[packs[i].ID]
+	}
+
+	return packs, nil
+}
+
+// RecalculateParTimes sets the par time of every puzzle with enough fully
+// correct completions to their median completion time. It returns the number
+// of puzzles updated.
+func (s *puzzleService) RecalculateParTimes() (int, error) {
+	stats, err := s.attemptRepo.FindCompletionTimeStats(ParTimeMinSamples)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, stat := range stats {
+		parTime := models.Seconds(math.Round(stat.MedianSeconds))
+		if err := s.puzzleRepo.UpdateParTime(stat.PuzzleID, parTime, stat.Samples); err != nil {
+			return i, err
+		}
+	}
+	return len(stats), nil
 }
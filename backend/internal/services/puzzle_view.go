@@ -0,0 +1,186 @@
+package services
+
+import (
+	"time"
+
+	"hh_puzzle/internal/models"
+)
+
+// PlayerClue is a clue as shown to players, without its answer
+type PlayerClue struct {
+	Number    int    `json:"number"`
+	Direction string `json:"direction"`
+	Text      string `json:"text"`
+	Length    int    `json:"length"`
+	X         int    `json:"x"`
+	Y         int    `json:"y"`
+}
+
+// PlayerGrid describes the shape of a puzzle grid without the solution.
+// All matrices are indexed [y][x].
+type PlayerGrid struct {
+	Width    int        `json:"width"`
+	Height   int        `json:"height"`
+	Blocks   [][]bool   `json:"blocks"`   // true for black squares
+	Numbers  [][]int    `json:"numbers"`  // clue number, 0 when unnumbered
+	Revealed [][]string `json:"revealed"` // letter shown at the start, empty otherwise
+}
+
+// PlayerPuzzle is the player-facing view of a puzzle. It never contains answers.
+type PlayerPuzzle struct {
+	ID                 uint           `json:"id"`
+	Title              string         `json:"title"`
+	Description        string         `json:"description"`
+	Grid               PlayerGrid     `json:"grid"`
+	CluesAcross        []PlayerClue   `json:"clues_across"`
+	CluesDown          []PlayerClue   `json:"clues_down"`
+	Difficulty         string         `json:"difficulty"`
+	Decade             string         `json:"decade,omitempty"`
+	Region             string         `json:"region,omitempty"`
+	Subgenre           string         `json:"subgenre,omitempty"`
+	ParTime            models.Seconds `json:"par_time_seconds,omitempty"`
+	BasePoints         int            `json:"base_points"`
+	IsDailyChallenge   bool           `json:"is_daily_challenge"`
+	DailyChallengeDate *time.Time     `json:"daily_challenge_date,omitempty"`
+	PuzzlePackID       *uint          `json:"puzzle_pack_id,omitempty"`
+}
+
+// PuzzlePreview is shown in place of a puzzle the player has not unlocked.
+// It describes the puzzle without its grid or clues.
+type PuzzlePreview struct {
+	ID           uint           `json:"id"`
+	Title        string         `json:"title"`
+	Description  string         `json:"description"`
+	Difficulty   string         `json:"difficulty"`
+	Decade       string         `json:"decade,omitempty"`
+	Region       string         `json:"region,omitempty"`
+	Subgenre     string         `json:"subgenre,omitempty"`
+	ParTime      models.Seconds `json:"par_time_seconds,omitempty"`
+	BasePoints   int            `json:"base_points"`
+	Width        int            `json:"width"`
+	Height       int            `json:"height"`
+	ClueCount    int            `json:"clue_count"`
+	PuzzlePackID *uint          `json:"puzzle_pack_id,omitempty"`
+	IsLocked     bool           `json:"is_locked"`
+}
+
+// PlayerAttempt is an attempt with its puzzle replaced by the player view
+type PlayerAttempt struct {
+	models.PuzzleAttempt
+	Puzzle *PlayerPuzzle `json:"puzzle,omitempty"`
+}
+
+// NewPlayerPuzzle builds the player view of a puzzle
+func NewPlayerPuzzle(puzzle *models.Puzzle) *PlayerPuzzle {
+	view := &PlayerPuzzle{
+		ID:                 puzzle.ID,
+		Title:              puzzle.Title,
+		Description:        puzzle.Description,
+		CluesAcross:        []PlayerClue{},
+		CluesDown:          []PlayerClue{},
+		Difficulty:         puzzle.Difficulty,
+		Decade:             puzzle.Decade,
+		Region:             puzzle.Region,
+		Subgenre:           puzzle.Subgenre,
+		ParTime:            puzzle.ParTime,
+		BasePoints:         puzzle.BasePoints,
+		IsDailyChallenge:   puzzle.IsDailyChallenge,
+		DailyChallengeDate: puzzle.DailyChallengeDate,
+		PuzzlePackID:       puzzle.PuzzlePackID,
+	}
+
+	for _, clue := range puzzle.CluesAcross {
+		view.CluesAcross = append(view.CluesAcross, newPlayerClue(clue))
+	}
+	for _, clue := range puzzle.CluesDown {
+		view.CluesDown = append(view.CluesDown, newPlayerClue(clue))
+	}
+
+	// Copy the grid shape, keeping only the letters revealed at the start
+	grid := puzzle.GridData
+	view.Grid = PlayerGrid{
+		Width:    grid.Width,
+		Height:   grid.Height,
+		Blocks:   grid.Blocks,
+		Numbers:  grid.Numbers,
+		Revealed: make([][]string, grid.Height),
+	}
+	for y := 0; y < grid.Height; y++ {
+		view.Grid.Revealed[y] = make([]string, grid.Width)
+		for x := 0; x < grid.Width; x++ {
+			if grid.Revealed[y][x] {
+				view.Grid.Revealed[y][x] = grid.Cells[y][x]
+			}
+		}
+	}
+
+	return view
+}
+
+// newPlayerClue strips the answer from a clue
+func newPlayerClue(clue models.Clue) PlayerClue {
+	return PlayerClue{
+		Number:    clue.Number,
+		Direction: clue.Direction,
+		Text:      clue.Text,
+		Length:    clue.Length,
+		X:         clue.X,
+		Y:         clue.Y,
+	}
+}
+
+// NewPuzzlePreview builds the locked preview of a puzzle
+func NewPuzzlePreview(puzzle *models.Puzzle) *PuzzlePreview {
+	return &PuzzlePreview{
+		ID:           puzzle.ID,
+		Title:        puzzle.Title,
+		Description:  puzzle.Description,
+		Difficulty:   puzzle.Difficulty,
+		Decade:       puzzle.Decade,
+		Region:       puzzle.Region,
+		Subgenre:     puzzle.Subgenre,
+		ParTime:      puzzle.ParTime,
+		BasePoints:   puzzle.BasePoints,
+		Width:        puzzle.GridData.Width,
+		Height:       puzzle.GridData.Height,
+		ClueCount:    len(puzzle.CluesAcross) + len(puzzle.CluesDown),
+		PuzzlePackID: puzzle.PuzzlePackID,
+		IsLocked:     true,
+	}
+}
+
+// NewPuzzlePreviews builds locked previews for a list of puzzles
+func NewPuzzlePreviews(puzzles []models.Puzzle) []*PuzzlePreview {
+	previews := make([]*PuzzlePreview, len(puzzles))
+	for i := range puzzles {
+		previews[i] = NewPuzzlePreview(&puzzles[i])
+	}
+	return previews
+}
+
+// NewPlayerPuzzles builds player views for a list of puzzles
+func NewPlayerPuzzles(puzzles []models.Puzzle) []*PlayerPuzzle {
+	views := make([]*PlayerPuzzle, len(puzzles))
+	for i := range puzzles {
+		views[i] = NewPlayerPuzzle(&puzzles[i])
+	}
+	return views
+}
+
+// NewPlayerAttempt builds the player view of an attempt
+func NewPlayerAttempt(attempt *models.PuzzleAttempt) *PlayerAttempt {
+	view := &PlayerAttempt{PuzzleAttempt: *attempt}
+	if attempt.Puzzle.ID != 0 {
+		view.Puzzle = NewPlayerPuzzle(&attempt.Puzzle)
+	}
+	return view
+}
+
+// NewPlayerAttempts builds player views for a list of attempts
+func NewPlayerAttempts(attempts []models.PuzzleAttempt) []*PlayerAttempt {
+	views := make([]*PlayerAttempt, len(attempts))
+	for i := range attempts {
+		views[i] = NewPlayerAttempt(&attempts[i])
+	}
+	return views
+}
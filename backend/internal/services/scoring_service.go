@@ -0,0 +1,258 @@
+package services
+
+import (
+	"math"
+
+	"hh_puzzle/internal/models"
+)
+
+// Scoring policy versions. Version 1 compared completion seconds with par
+// times in minutes; it is kept so scores recorded under it can be recalculated.
+const (
+	ScoringPolicyV1 = "default-v1"
+	ScoringPolicyV2 = "default-v2"
+
+	// DefaultScoringPolicyVersion is the version new attempts are scored with
+	DefaultScoringPolicyVersion = ScoringPolicyV2
+)
+
+// ScoreInput is what a scoring policy needs to score a completed attempt
+type ScoreInput struct {
+	Puzzle         *models.Puzzle
+	Accuracy       float64 // percentage of correct cells
+	CompletionTime models.Seconds
+	ParTime        models.Seconds // replaces the puzzle's par time when set
+	HintsUsed      int
+	Streak         int // the player's streak including this completion
+}
+
+// ScoringPolicy turns a completed attempt into points. Policies are versioned
+// so recorded scores can be explained and recalculated with the same rules.
+type ScoringPolicy interface {
+	Version() string
+	WithOverrides(overrides models.ScoringOverrides) ScoringPolicy
+	Score(input ScoreInput) models.ScoreBreakdown
+}
+
+// defaultScoringPolicy scores base points scaled by accuracy, a bonus for
+// beating the par time, a penalty per hint and an accuracy bonus, then
+// applies the multipliers set by overrides
+type defaultScoringPolicy struct {
+	version           string
+	hintPenalty       int
+	maxTimeBonus      int
+	accuracyBonusRate float64
+	overrides         models.ScoringOverrides
+
+	// legacyMinutes reproduces version 1, which read the par time as a
+	// number of minutes and compared it with seconds, defaulting to 300
+	legacyMinutes bool
+}
+
+// scoringPolicies holds every policy version attempts may have been scored with
+var scoringPolicies = map[string]ScoringPolicy{
+	ScoringPolicyV1: &defaultScoringPolicy{
+		version:           ScoringPolicyV1,
+		hintPenalty:       10,
+		maxTimeBonus:      50,
+		accuracyBonusRate: 0.5,
+		legacyMinutes:     true,
+	},
+	ScoringPolicyV2: &defaultScoringPolicy{
+		version:           ScoringPolicyV2,
+		hintPenalty:       10,
+		maxTimeBonus:      50,
+		accuracyBonusRate: 0.5,
+	},
+}
+
+// DefaultScoringPolicy returns the policy new attempts are scored with
+func DefaultScoringPolicy() ScoringPolicy {
+	return scoringPolicies[DefaultScoringPolicyVersion]
+}
+
+// LookupScoringPolicy returns the policy with the given version
+func LookupScoringPolicy(version string) (ScoringPolicy, error) {
+	policy, ok := scoringPolicies[version]
+	if !ok {
+		return nil, ErrUnknownScoringPolicy
+	}
+	return policy, nil
+}
+
+func (p *defaultScoringPolicy) Version() string {
+	return p.version
+}
+
+// WithOverrides returns a copy of the policy with the overrides applied on top
+// of any it already has
+func (p *defaultScoringPolicy) WithOverrides(overrides models.ScoringOverrides) ScoringPolicy {
+	policy := *p
+	policy.overrides = p.overrides.Merge(overrides)
+	return &policy
+}
+
+func (p *defaultScoringPolicy) Score(input ScoreInput) models.ScoreBreakdown {
+	hintPenalty := p.hintPenalty
+	if p.overrides.HintPenalty != nil {
+		hintPenalty = *p.overrides.HintPenalty
+	}
+	maxTimeBonus := p.maxTimeBonus
+	if p.overrides.MaxTimeBonus != nil {
+		maxTimeBonus = *p.overrides.MaxTimeBonus
+	}
+
+	parTime := input.ParTime
+	if parTime == 0 {
+		parTime = input.Puzzle.ParTime
+	}
+	if parTime == 0 && !p.legacyMinutes {
+		parTime = models.DefaultsForDifficulty(input.Puzzle.Difficulty).ParTime
+	}
+
+	breakdown := models.ScoreBreakdown{
+		PolicyVersion:  p.version,
+		Accuracy:       input.Accuracy,
+		CompletionTime: input.CompletionTime,
+		ParTime:        parTime,
+		HintsUsed:      input.HintsUsed,
+		Streak:         input.Streak,
+		Items: []models.ScoreItem{
+			{Rule: models.ScoreRuleBase, Points: int(float64(input.Puzzle.BasePoints) * input.Accuracy / 100)},
+			{Rule: models.ScoreRuleTimeBonus, Points: p.timeBonus(input.CompletionTime, parTime, maxTimeBonus)},
+			{Rule: models.ScoreRuleHintPenalty, Points: -input.HintsUsed * hintPenalty},
+			{Rule: models.ScoreRuleAccuracyBonus, Points: int(input.Accuracy * p.accuracyBonusRate)},
+		},
+	}
+	if !p.overrides.IsZero() {
+		overrides := p.overrides
+		breakdown.Overrides = &overrides
+	}
+
+	for _, item := range breakdown.Items {
+		breakdown.Subtotal += item.Points
+	}
+	if breakdown.Subtotal < 0 {
+		breakdown.Subtotal = 0
+	}
+
+	if p.overrides.Multiplier > 0 && p.overrides.Multiplier != 1 {
+		breakdown.Multipliers = append(breakdown.Multipliers, models.ScoreMultiplier{
+			Rule:   models.ScoreRuleMultiplier,
+			Factor: p.overrides.Multiplier,
+		})
+	}
+	if input.Puzzle.IsDailyChallenge && p.overrides.DailyChallengeMultiplier > 0 && p.overrides.DailyChallengeMultiplier != 1 {
+		breakdown.Multipliers = append(breakdown.Multipliers, models.ScoreMultiplier{
+			Rule:   models.ScoreRuleDailyChallenge,
+			Factor: p.overrides.DailyChallengeMultiplier,
+		})
+	}
+	if bonus := p.streakBonus(input.Streak); bonus > 0 {
+		breakdown.Multipliers = append(breakdown.Multipliers, models.ScoreMultiplier{
+			Rule:   models.ScoreRuleStreak,
+			Factor: 1 + bonus,
+		})
+	}
+
+	total := float64(breakdown.Subtotal)
+	for _, multiplier := range breakdown.Multipliers {
+		total *= multiplier.Factor
+	}
+	breakdown.Total = int(math.Round(total))
+
+	return breakdown
+}
+
+// timeBonus awards up to maxBonus points for finishing faster than the par time
+func (p *defaultScoringPolicy) timeBonus(completionTime, parTime models.Seconds, maxBonus int) int {
+	if p.legacyMinutes {
+		parTime /= 60
+		if parTime == 0 {
+			parTime = 300
+		}
+	}
+	if parTime <= 0 {
+		return 0
+	}
+
+	// Bonus if completed faster than par
+	if completionTime < parTime {
+		percentFaster := float64(parTime-completionTime) / float64(parTime)
+		return int(percentFaster * float64(maxBonus))
+	}
+
+	return 0
+}
+
+// streakBonus returns the multiplier's extra for a streak, capped when a cap is set
+func (p *defaultScoringPolicy) streakBonus(streak int) float64 {
+	if p.overrides.StreakBonusPerDay <= 0 || streak <= 1 {
+		return 0
+	}
+
+	// The first day of a streak earns no bonus
+	bonus := float64(streak-1) * p.overrides.StreakBonusPerDay
+	if p.overrides.MaxStreakBonus > 0 && bonus > p.overrides.MaxStreakBonus {
+		bonus = p.overrides.MaxStreakBonus
+	}
+	return bonus
+}
+
+// ScoringService picks the scoring policy for a puzzle and scores attempts with it
+type ScoringService interface {
+	PolicyFor(puzzle *models.Puzzle) ScoringPolicy
+	Score(input ScoreInput) models.ScoreBreakdown
+	Recalculate(puzzle *models.Puzzle, breakdown *models.ScoreBreakdown, version string) (*models.ScoreBreakdown, error)
+}
+
+type scoringService struct {
+	policy ScoringPolicy
+}
+
+// NewScoringService creates a new scoring service that scores with the given policy
+func NewScoringService(policy ScoringPolicy) ScoringService {
+	return &scoringService{policy: policy}
+}
+
+// PolicyFor returns the policy with the puzzle's pack overrides applied, then
+// the puzzle's own overrides, which take precedence
+func (s *scoringService) PolicyFor(puzzle *models.Puzzle) ScoringPolicy {
+	policy := s.policy
+	if puzzle.PuzzlePack != nil && !puzzle.PuzzlePack.ScoringOverrides.IsZero() {
+		policy = policy.WithOverrides(puzzle.PuzzlePack.ScoringOverrides)
+	}
+	if !puzzle.ScoringOverrides.IsZero() {
+		policy = policy.WithOverrides(puzzle.ScoringOverrides)
+	}
+	return policy
+}
+
+func (s *scoringService) Score(input ScoreInput) models.ScoreBreakdown {
+	return s.PolicyFor(input.Puzzle).Score(input)
+}
+
+// Recalculate scores a recorded breakdown's inputs again with the overrides it
+// was scored with. An empty version uses the breakdown's own policy version.
+func (s *scoringService) Recalculate(puzzle *models.Puzzle, breakdown *models.ScoreBreakdown, version string) (*models.ScoreBreakdown, error) {
+	if version == "" {
+		version = breakdown.PolicyVersion
+	}
+	policy, err := LookupScoringPolicy(version)
+	if err != nil {
+		return nil, err
+	}
+	if breakdown.Overrides != nil {
+		policy = policy.WithOverrides(*breakdown.Overrides)
+	}
+
+	recalculated := policy.Score(ScoreInput{
+		Puzzle:         puzzle,
+		Accuracy:       breakdown.Accuracy,
+		CompletionTime: breakdown.CompletionTime,
+		ParTime:        breakdown.ParTime,
+		HintsUsed:      breakdown.HintsUsed,
+		Streak:         breakdown.Streak,
+	})
+	return &recalculated, nil
+}
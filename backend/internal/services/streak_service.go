@@ -0,0 +1,228 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"hh_puzzle/internal/models"
+	"hh_puzzle/internal/repository"
+)
+
+// Streak freeze rules
+const (
+	MaxStreakFreezes         = 3   // freezes a player can hold at once
+	StreakFreezeEarnInterval = 7   // a freeze is earned every this many streak days
+	StreakFreezePrice        = 500 // points spent to buy a freeze
+)
+
+// StreakUpdate describes how a completion changed a player's streak
+type StreakUpdate struct {
+	Streak        int         `json:"streak"`
+	FrozenDays    []time.Time `json:"frozen_days,omitempty"` // missed days covered by freezes
+	FreezesEarned int         `json:"freezes_earned,omitempty"`
+}
+
+// StreakCalendar is a player's streak with its recent history
+type StreakCalendar struct {
+	CurrentStreak    int                `json:"current_streak"`
+	LongestStreak    int                `json:"longest_streak"`
+	IsAlive          bool               `json:"is_alive"` // playing today continues the streak
+	FreezesRemaining int                `json:"freezes_remaining"`
+	MaxFreezes       int                `json:"max_freezes"`
+	FreezePrice      int                `json:"freeze_price"`
+	Timezone         string             `json:"timezone"`
+	Today            time.Time          `json:"today"`
+	Days             []models.StreakDay `json:"days"`
+}
+
+// StreakService tracks daily play streaks, their history and streak freezes
+type StreakService interface {
+	WithTx(tx *repository.Tx) StreakService
+	RecordCompletion(userID uint, profile *models.UserProfile, now time.Time) (*StreakUpdate, error)
+	GetStreak(userID uint, days int) (*StreakCalendar, error)
+	BuyFreeze(userID uint) (*StreakCalendar, error)
+}
+
+type streakService struct {
+	streakRepo repository.StreakRepository
+	userRepo   repository.UserRepository
+	txManager  repository.TxManager
+}
+
+// NewStreakService creates a new streak service
+func NewStreakService(streakRepo repository.StreakRepository, userRepo repository.UserRepository, txManager repository.TxManager) StreakService {
+	return &streakService{
+		streakRepo: streakRepo,
+		userRepo:   userRepo,
+		txManager:  txManager,
+	}
+}
+
+// WithTx returns a copy of the service whose repositories run inside the transaction
+func (s *streakService) WithTx(tx *repository.Tx) StreakService {
+	return &streakService{
+		streakRepo: s.streakRepo.WithTx(tx),
+		userRepo:   s.userRepo.WithTx(tx),
+		txManager:  s.txManager,
+	}
+}
+
+// RecordCompletion advances the streak on the profile for a completion at now
+// and writes the played and frozen days to the history. The caller saves the profile.
+func (s *streakService) RecordCompletion(userID uint, profile *models.UserProfile, now time.Time) (*StreakUpdate, error) {
+	today := LocalDate(now, UserLocation(profile))
+	update := AdvanceStreak(profile, today)
+
+	if err := s.streakRepo.RecordFrozen(userID, update.FrozenDays); err != nil {
+		return nil, fmt.Errorf("failed to record frozen days: %w", err)
+	}
+	if err := s.streakRepo.RecordPlayed(userID, today); err != nil {
+		return nil, fmt.Errorf("failed to record played day: %w", err)
+	}
+
+	return &update, nil
+}
+
+// GetStreak returns the streak and the history of the last number of days
+func (s *streakService) GetStreak(userID uint, days int) (*StreakCalendar, error) {
+	if days < 1 || days > 366 {
+		days = 30
+	}
+
+	profile, err := s.getProfile(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := LocalDate(time.Now(), UserLocation(profile))
+	history, err := s.streakRepo.FindBetween(userID, today.AddDate(0, 0, 1-days), today)
+	if err != nil {
+		return nil, err
+	}
+
+	return newStreakCalendar(profile, today, history), nil
+}
+
+// BuyFreeze spends points on one streak freeze. The profile row is locked
+// while the purchase is checked and saved, as it is when an attempt is
+// submitted, so concurrent purchases and submissions cannot overwrite it.
+func (s *streakService) BuyFreeze(userID uint) (*StreakCalendar, error) {
+	err := s.txManager.Transaction(func(tx *repository.Tx) error {
+		userRepo := s.userRepo.WithTx(tx)
+
+		profile, err := userRepo.FindProfileForUpdate(userID)
+		if err != nil {
+			return err
+		}
+		if profile.StreakFreezes >= MaxStreakFreezes {
+			return ErrFreezeLimitReached
+		}
+		if profile.TotalPoints < StreakFreezePrice {
+			return ErrNotEnoughPoints
+		}
+
+		profile.TotalPoints -= StreakFreezePrice
+		profile.StreakFreezes++
+		if err := userRepo.UpdateProfile(profile); err != nil {
+			return fmt.Errorf("failed to buy streak freeze: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetStreak(userID, 0)
+}
+
+func (s *streakService) getProfile(userID uint) (*models.UserProfile, error) {
+	user, err := s.userRepo.GetWithProfile(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Profile == nil {
+		return nil, errors.New("profile not found")
+	}
+	return user.Profile, nil
+}
+
+// AdvanceStreak records a completion on the player's local calendar date today.
+// Dates are compared as calendar days, so DST changes never break a streak. A
+// date earlier than the last one played, as happens after flying west across
+// the date line, counts as the same day. Missed days are covered by streak
+// freezes when the player holds enough of them for the whole gap.
+func AdvanceStreak(profile *models.UserProfile, today time.Time) StreakUpdate {
+	update := StreakUpdate{}
+
+	if profile.LastPuzzleDate == nil {
+		// First puzzle
+		profile.CurrentStreak = 1
+	} else {
+		lastDate := dateOf(*profile.LastPuzzleDate)
+		missed := daysBetween(lastDate, today) - 1
+
+		switch {
+		case missed < 0:
+			// Same day - maintain streak
+			if profile.CurrentStreak == 0 {
+				profile.CurrentStreak = 1
+			}
+			update.Streak = profile.CurrentStreak
+			return update
+		case missed == 0:
+			// Consecutive day - increment streak
+			profile.CurrentStreak++
+		case missed <= profile.StreakFreezes:
+			// Freezes cover every missed day
+			for i := 1; i <= missed; i++ {
+				update.FrozenDays = append(update.FrozenDays, lastDate.AddDate(0, 0, i))
+			}
+			profile.StreakFreezes -= missed
+			profile.CurrentStreak++
+		default:
+			// Streak broken - reset to 1
+			profile.CurrentStreak = 1
+		}
+	}
+
+	if profile.CurrentStreak%StreakFreezeEarnInterval == 0 && profile.StreakFreezes < MaxStreakFreezes {
+		profile.StreakFreezes++
+		update.FreezesEarned = 1
+	}
+	if profile.CurrentStreak > profile.LongestStreak {
+		profile.LongestStreak = profile.CurrentStreak
+	}
+	profile.LastPuzzleDate = &today
+
+	update.Streak = profile.CurrentStreak
+	return update
+}
+
+// newStreakCalendar reports whether the streak can still be continued today
+func newStreakCalendar(profile *models.UserProfile, today time.Time, history []models.StreakDay) *StreakCalendar {
+	calendar := &StreakCalendar{
+		CurrentStreak:    profile.CurrentStreak,
+		LongestStreak:    profile.LongestStreak,
+		FreezesRemaining: profile.StreakFreezes,
+		MaxFreezes:       MaxStreakFreezes,
+		FreezePrice:      StreakFreezePrice,
+		Timezone:         UserLocation(profile).String(),
+		Today:            today,
+		Days:             history,
+	}
+
+	if profile.LastPuzzleDate != nil && profile.CurrentStreak > 0 {
+		missed := daysBetween(dateOf(*profile.LastPuzzleDate), today) - 1
+		calendar.IsAlive = missed <= profile.StreakFreezes
+	}
+	if !calendar.IsAlive {
+		calendar.CurrentStreak = 0
+	}
+	return calendar
+}
+
+// daysBetween counts calendar days from one date to another
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
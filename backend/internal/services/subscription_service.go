@@ -0,0 +1,159 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"hh_puzzle/internal/models"
+	"hh_puzzle/internal/repository"
+)
+
+// SubscriptionGracePeriod is how long an active subscription keeps access
+// after its end date while the provider retries the renewal payment
+const SubscriptionGracePeriod = 3 * 24 * time.Hour
+
+// SubscriptionView is a subscription as shown to its owner
+type SubscriptionView struct {
+	models.Purchase
+	AccessEndsAt  time.Time `json:"access_ends_at"`
+	WillRenew     bool      `json:"will_renew"`
+	InGracePeriod bool      `json:"in_grace_period"`
+}
+
+// SubscriptionService manages subscription periods, renewals and cancellation
+type SubscriptionService interface {
+	Start(purchase *models.Purchase, now time.Time)
+	Renew(purchase *models.Purchase, periodEnd time.Time) error
+	Cancel(userID, purchaseID uint) (*SubscriptionView, error)
+	HasAccess(userID, packID uint, now time.Time) (bool, error)
+	ExpireLapsed(now time.Time) (int64, error)
+	GetUserSubscriptions(userID uint) ([]SubscriptionView, error)
+}
+
+type subscriptionService struct {
+	purchaseRepo repository.PurchaseRepository
+}
+
+// NewSubscriptionService creates a new subscription service
+func NewSubscriptionService(purchaseRepo repository.PurchaseRepository) SubscriptionService {
+	return &subscriptionService{
+		purchaseRepo: purchaseRepo,
+	}
+}
+
+// Start sets up the first period of a newly paid subscription. The caller saves the purchase.
+func (s *subscriptionService) Start(purchase *models.Purchase, now time.Time) {
+	end := nextPeriodEnd(now)
+	purchase.SubscriptionStartDate = &now
+	purchase.SubscriptionEndDate = &end
+	purchase.SubscriptionStatus = models.SubscriptionStatusActive
+}
+
+// Renew extends a subscription to the end of a period the provider charged
+// for. A renewal whose period has already been applied changes nothing, and a
+// cancelled subscription stays cancelled so it will not renew again. When the
+// subscription changed since it was read, ErrSubscriptionConflict is returned
+// and the provider's redelivery applies the renewal.
+func (s *subscriptionService) Renew(purchase *models.Purchase, periodEnd time.Time) error {
+	if !purchase.IsSubscription || purchase.Status != models.PurchaseStatusCompleted {
+		return ErrSubscriptionNotFound
+	}
+	if purchase.SubscriptionEndDate != nil && !purchase.SubscriptionEndDate.Before(periodEnd) {
+		return nil
+	}
+
+	fromStatus, fromEnd := purchase.SubscriptionStatus, purchase.SubscriptionEndDate
+	purchase.SubscriptionEndDate = &periodEnd
+	if purchase.SubscriptionStatus != models.SubscriptionStatusCancelled {
+		purchase.SubscriptionStatus = models.SubscriptionStatusActive
+	}
+
+	updated, err := s.purchaseRepo.UpdateSubscription(purchase, fromStatus, fromEnd)
+	if err != nil {
+		return fmt.Errorf("failed to renew subscription: %w", err)
+	}
+	if !updated {
+		return ErrSubscriptionConflict
+	}
+	return nil
+}
+
+// Cancel stops a subscription from renewing. Access continues until the end
+// of the period that was already paid for. A renewal landing at the same time
+// makes it fail with ErrSubscriptionConflict rather than be lost.
+func (s *subscriptionService) Cancel(userID, purchaseID uint) (*SubscriptionView, error) {
+	purchase, err := s.purchaseRepo.FindByID(purchaseID)
+	if err != nil || !purchase.IsSubscription || purchase.Status != models.PurchaseStatusCompleted {
+		return nil, ErrSubscriptionNotFound
+	}
+	if purchase.UserID != userID {
+		return nil, ErrSubscriptionNotFound
+	}
+
+	switch purchase.SubscriptionStatus {
+	case models.SubscriptionStatusCancelled:
+		// Already cancelled, nothing changes
+	case models.SubscriptionStatusActive:
+		purchase.SubscriptionStatus = models.SubscriptionStatusCancelled
+		updated, err := s.purchaseRepo.UpdateSubscription(purchase, models.SubscriptionStatusActive, purchase.SubscriptionEndDate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to cancel subscription: %w", err)
+		}
+		if !updated {
+			return nil, ErrSubscriptionConflict
+		}
+	default:
+		return nil, ErrSubscriptionExpired
+	}
+
+	view := newSubscriptionView(*purchase, time.Now())
+	return &view, nil
+}
+
+// HasAccess reports whether any subscription of the user currently covers the pack
+func (s *subscriptionService) HasAccess(userID, packID uint, now time.Time) (bool, error) {
+	return s.purchaseRepo.HasActiveSubscription(userID, packID, now, now.Add(-SubscriptionGracePeriod))
+}
+
+// ExpireLapsed marks cancelled subscriptions past their end date, and active
+// ones past their grace period, as expired
+func (s *subscriptionService) ExpireLapsed(now time.Time) (int64, error) {
+	return s.purchaseRepo.ExpireSubscriptions(now, now.Add(-SubscriptionGracePeriod))
+}
+
+func (s *subscriptionService) GetUserSubscriptions(userID uint) ([]SubscriptionView, error) {
+	purchases, err := s.purchaseRepo.FindSubscriptionsByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	views := make([]SubscriptionView, len(purchases))
+	for i, purchase := range purchases {
+		views[i] = newSubscriptionView(purchase, now)
+	}
+	return views, nil
+}
+
+// newSubscriptionView works out when a subscription's access ends
+func newSubscriptionView(purchase models.Purchase, now time.Time) SubscriptionView {
+	view := SubscriptionView{
+		Purchase:  purchase,
+		WillRenew: purchase.SubscriptionStatus == models.SubscriptionStatusActive,
+	}
+	if purchase.SubscriptionEndDate == nil {
+		return view
+	}
+
+	view.AccessEndsAt = *purchase.SubscriptionEndDate
+	if view.WillRenew {
+		view.AccessEndsAt = view.AccessEndsAt.Add(SubscriptionGracePeriod)
+		view.InGracePeriod = now.After(*purchase.SubscriptionEndDate) && now.Before(view.AccessEndsAt)
+	}
+	return view
+}
+
+// nextPeriodEnd returns the end of a monthly billing period starting at from
+func nextPeriodEnd(from time.Time) time.Time {
+	return from.AddDate(0, 1, 0)
+}
@@ -19,7 +19,7 @@ type UserStats struct {
 type UserService interface {
 	GetProfile(userID uint) (*models.UserProfile, error)
 	UpdateProfile(userID uint, displayName, avatarURL string) error
-	UpdatePreferences(userID uint, musicEnabled bool, musicVolume int, theme, difficulty string) error
+	UpdatePreferences(userID uint, musicEnabled bool, musicVolume int, theme, difficulty, timezone string) error
 	GetUserStats(userID uint) (*UserStats, error)
 	DeleteAccount(userID uint) error
 }
@@ -66,10 +66,10 @@ func (s *userService) UpdateProfile(userID uint, displayName, avatarURL string)
 		user.Profile.AvatarURL = avatarURL
 	}
 
-	return s.userRepo.Update(user)
+	return s.userRepo.UpdateProfile(user.Profile)
 }
 
-func (s *userService) UpdatePreferences(userID uint, musicEnabled bool, musicVolume int, theme, difficulty string) error {
+func (s *userService) UpdatePreferences(userID uint, musicEnabled bool, musicVolume int, theme, difficulty, timezone string) error {
 	user, err := s.userRepo.GetWithProfile(userID)
 	if err != nil {
 		return err
@@ -94,6 +94,13 @@ func (s *userService) UpdatePreferences(userID uint, musicEnabled bool, musicVol
 		return errors.New("difficulty must be 'beginner', 'intermediate', or 'expert'")
 	}
 
+	// Validate timezone
+	if timezone != "" {
+		if _, err := LoadTimezone(timezone); err != nil {
+			return err
+		}
+	}
+
 	// Update preferences
 	user.Profile.MusicEnabled = musicEnabled
 	user.Profile.MusicVolume = musicVolume
@@ -103,8 +110,11 @@ func (s *userService) UpdatePreferences(userID uint, musicEnabled bool, musicVol
 	if difficulty != "" {
 		user.Profile.DifficultyPreference = difficulty
 	}
+	if timezone != "" {
+		user.Profile.Timezone = timezone
+	}
 
-	return s.userRepo.Update(user)
+	return s.userRepo.UpdateProfile(user.Profile)
 }
 
 func (s *userService) GetUserStats(userID uint) (*UserStats, error) {